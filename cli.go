@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, `Usage: news-bot <command> [flags]
+
+Commands:
+  run        generate and publish one post from the topic rotation (default)
+  serve      run continuously, posting on SCHEDULE and per-topic schedules
+  live       follow matches in play and post goal, half-time and full-time alerts
+  generate   run the fetch + LLM pipeline for one topic and print the result
+  help       show this message
+
+Run "news-bot generate -h" for generate flags.
+`)
+}
+
+// newBotFromEnv loads configuration from the environment and creates the bot,
+// exiting on failure.
+func newBotFromEnv(dryRun bool) *NewsBot {
+	config, err := loadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	config.DryRun = dryRun
+
+	bot, err := NewNewsBot(config)
+	if err != nil {
+		log.Fatalf("Failed to create news bot: %v", err)
+	}
+	return bot
+}
+
+func runCommand(args []string) {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.Parse(args)
+
+	bot := newBotFromEnv(false)
+	defer bot.Close()
+
+	// Add credential debugging
+	bot.debugCredentials()
+
+	if err := bot.Run(context.Background()); err != nil {
+		log.Fatalf("Bot execution failed: %v", err)
+	}
+	log.Println("Bot execution completed successfully!")
+}
+
+func serveCommand(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.Parse(args)
+
+	bot := newBotFromEnv(false)
+	defer bot.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := bot.Serve(ctx); err != nil {
+		log.Fatalf("Scheduler failed: %v", err)
+	}
+}
+
+func liveCommand(args []string) {
+	fs := flag.NewFlagSet("live", flag.ExitOnError)
+	fs.Parse(args)
+
+	bot := newBotFromEnv(false)
+	defer bot.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := bot.Live(ctx); err != nil {
+		log.Fatalf("Live mode failed: %v", err)
+	}
+}
+
+// generateOutput is what the generate command prints.
+type generateOutput struct {
+	Topic      string      `json:"topic"`
+	Text       string      `json:"text"`
+	Characters int         `json:"characters"`
+	Limit      int         `json:"limit"`
+	Valid      bool        `json:"valid"`
+	Error      string      `json:"error,omitempty"`
+	Provider   string      `json:"provider"`
+	Model      string      `json:"model"`
+	Source     interface{} `json:"source,omitempty"`
+	Segments   []string    `json:"segments,omitempty"` // the posts of an explicit thread
+	Media      []string    `json:"media,omitempty"`    // files written by --save-media
+	Posted     bool        `json:"posted"`
+}
+
+// generateCommand runs the full pipeline for one topic and prints the
+// result. With --dry-run nothing is posted or recorded. It returns the
+// process exit code: non-zero when generation or validation fails.
+func generateCommand(args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	topicName := fs.String("topic", "", "topic name to generate, e.g. PL (default: draw from the rotation)")
+	dryRun := fs.Bool("dry-run", false, "print the post without publishing it")
+	format := fs.String("format", "text", "output format: text or json")
+	timeout := fs.Duration("timeout", 2*time.Minute, "maximum time for the pipeline")
+	saveMedia := fs.String("save-media", "", "directory to write attached images to for inspection")
+	fs.Parse(args)
+
+	if *format != "text" && *format != "json" {
+		log.Printf("Unknown --format %q (expected text or json)", *format)
+		return 2
+	}
+
+	bot := newBotFromEnv(*dryRun)
+	defer bot.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	topics := bot.eligibleTopics(time.Now(), bot.topics)
+	if *topicName != "" {
+		topic := bot.findTopic(*topicName)
+		if topic == nil {
+			log.Printf("Unknown topic %q", *topicName)
+			return 2
+		}
+		topics = []*Topic{topic}
+	}
+
+	var topic *Topic
+	var draft *Draft
+	for _, t := range topics {
+		d, err := bot.generateTopic(ctx, t)
+		if errors.Is(err, errNothingNew) {
+			log.Printf("Nothing new for %s", t.DisplayName)
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to generate %s content: %v", t.DisplayName, err)
+			return 1
+		}
+		topic, draft = t, d
+		break
+	}
+	if draft == nil {
+		log.Println("Nothing to generate")
+		return 1
+	}
+
+	out := generateOutput{
+		Topic:      topic.Name,
+		Text:       draft.Text,
+		Characters: tweetWeightedLength(draft.Text),
+		Limit:      bot.textLimit(topic),
+		Valid:      true,
+		Provider:   draft.Provider,
+		Model:      draft.Model,
+		Source:     draft.Source,
+		Segments:   draft.Segments,
+	}
+	if err := bot.validateDraft(draft, topic); err != nil {
+		out.Valid = false
+		out.Error = err.Error()
+	}
+	if *saveMedia != "" {
+		for i, m := range draft.Media {
+			path := filepath.Join(*saveMedia, fmt.Sprintf("%s-%d.png", topic.Name, i+1))
+			if err := os.WriteFile(path, m.Data, 0o644); err != nil {
+				log.Printf("Failed to save media: %v", err)
+				continue
+			}
+			out.Media = append(out.Media, path)
+		}
+	}
+
+	if out.Valid && !*dryRun {
+		results, err := bot.publish(ctx, draft.post(topic))
+		if err != nil {
+			log.Printf("Failed to publish: %v", err)
+		} else {
+			out.Posted = true
+			if err := bot.recordPost(draft, results); err != nil {
+				log.Printf("Warning: failed to record post history: %v", err)
+			}
+		}
+	}
+
+	printGenerateOutput(&out, topic, *format)
+	if !out.Valid || (!*dryRun && !out.Posted) {
+		return 1
+	}
+	return 0
+}
+
+func printGenerateOutput(out *generateOutput, topic *Topic, format string) {
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(out)
+		return
+	}
+
+	fmt.Printf("Topic:      %s (%s)\n", topic.DisplayName, topic.Name)
+	fmt.Printf("Provider:   %s (%s)\n", out.Provider, out.Model)
+	fmt.Printf("Characters: %d/%d\n", out.Characters, out.Limit)
+	if out.Valid {
+		fmt.Println("Valid:      yes")
+	} else {
+		fmt.Printf("Valid:      no (%s)\n", out.Error)
+	}
+	if out.Source != nil {
+		source, _ := json.MarshalIndent(out.Source, "", "  ")
+		fmt.Printf("Source:\n%s\n", source)
+	}
+	for _, path := range out.Media {
+		fmt.Printf("Media:      %s\n", path)
+	}
+	if len(out.Segments) > 0 {
+		for i, segment := range out.Segments {
+			fmt.Printf("\n--- %d/%d (%d characters)\n%s\n", i+1, len(out.Segments), tweetWeightedLength(segment), segment)
+		}
+		return
+	}
+	fmt.Printf("\n%s\n", out.Text)
+}
+
+// findTopic looks a topic up by name, ignoring case.
+func (nb *NewsBot) findTopic(name string) *Topic {
+	for _, t := range nb.topics {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestFindTopic(t *testing.T) {
+	nb := &NewsBot{topics: []*Topic{{Name: "PL"}, {Name: "crypto"}}}
+	if got := nb.findTopic("pl"); got == nil || got.Name != "PL" {
+		t.Errorf("findTopic(pl) = %v, want PL", got)
+	}
+	if got := nb.findTopic("NBA"); got != nil {
+		t.Errorf("findTopic(NBA) = %v, want nil", got)
+	}
+}
+
+func TestGenerateCommandRejectsUnknownFormat(t *testing.T) {
+	if code := generateCommand([]string{"--format", "yaml"}); code != 2 {
+		t.Errorf("exit code = %d, want 2", code)
+	}
+}
+
+func TestPrintGenerateOutputJSON(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	printGenerateOutput(&generateOutput{Topic: "PL", Text: "Full time", Characters: 9, Limit: 280, Valid: true},
+		&Topic{Name: "PL", DisplayName: "Premier League"}, "json")
+	os.Stdout = stdout
+	w.Close()
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out generateOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, raw)
+	}
+	if out.Topic != "PL" || out.Text != "Full time" || !out.Valid || out.Limit != 280 {
+		t.Errorf("output = %+v", out)
+	}
+}
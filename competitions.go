@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Competition is an entry in the competition registry: a league, cup or
+// continental competition and how posts about it are written.
+type Competition struct {
+	Code     string   `json:"code"` // football-data.org competition code, e.g. "PL" or "CL"
+	Name     string   `json:"name"`
+	Hashtags []string `json:"hashtags,omitempty"`
+	Language string   `json:"language,omitempty"` // language posts are written in, e.g. "Spanish"; default English
+	Timezone string   `json:"timezone,omitempty"` // default timezone for the competition's topics
+	Provider string   `json:"provider,omitempty"` // where match data comes from, default "football-data"
+	Fallback string   `json:"fallback,omitempty"` // provider tried when Provider fails
+
+	SportsDBID string `json:"thesportsdb_id,omitempty"` // TheSportsDB league ID
+}
+
+// footballProviders are the match data sources a competition may use.
+var footballProviders = map[string]bool{"football-data": true, "thesportsdb": true}
+
+// bigFiveLeagues are compared by scorers-compare topics by default.
+var bigFiveLeagues = []string{"PL", "PD", "BL1", "SA", "FL1"}
+
+func defaultCompetitions() []*Competition {
+	// Served by football-data.org, with TheSportsDB as the fallback.
+	competition := func(code, name, tag, tz, sportsDBID string) *Competition {
+		return &Competition{
+			Code:       code,
+			Name:       name,
+			Hashtags:   []string{"#" + tag, "#Football"},
+			Timezone:   tz,
+			Fallback:   "thesportsdb",
+			SportsDBID: sportsDBID,
+		}
+	}
+	return []*Competition{
+		competition("PL", "Premier League", "PremierLeague", "Europe/London", "4328"),
+		competition("ELC", "Championship", "EFLChampionship", "Europe/London", "4329"),
+		competition("PD", "La Liga", "LaLiga", "Europe/Madrid", "4335"),
+		competition("BL1", "Bundesliga", "Bundesliga", "Europe/Berlin", "4331"),
+		competition("SA", "Serie A", "SerieA", "Europe/Rome", "4332"),
+		competition("FL1", "Ligue 1", "Ligue1", "Europe/Paris", "4334"),
+		competition("DED", "Eredivisie", "Eredivisie", "Europe/Amsterdam", "4337"),
+		competition("PPL", "Primeira Liga", "LigaPortugal", "Europe/Lisbon", "4344"),
+		competition("BSA", "Brasileirão", "Brasileirao", "America/Sao_Paulo", "4351"),
+		competition("CL", "Champions League", "UCL", "", "4480"),
+		competition("EC", "European Championship", "EURO", "", "4502"),
+		competition("WC", "World Cup", "WorldCup", "", "4429"),
+		// Not on football-data.org's free tier.
+		{
+			Code:       "IRL",
+			Name:       "Irish Premier Division",
+			Hashtags:   []string{"#IrishPremierDivision", "#Football"},
+			Timezone:   "Europe/Dublin",
+			Provider:   "thesportsdb",
+			SportsDBID: "4643",
+		},
+	}
+}
+
+// loadCompetitions builds the competition registry from the built-in
+// competitions and the entries in path, which add to or replace them by code.
+// A missing file leaves the built-ins as they are.
+func loadCompetitions(path string) (map[string]*Competition, error) {
+	competitions := defaultCompetitions()
+	raw, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read competitions file: %v", err)
+	default:
+		var custom []*Competition
+		if err := json.Unmarshal(raw, &custom); err != nil {
+			return nil, fmt.Errorf("failed to parse competitions file %s: %v", path, err)
+		}
+		competitions = append(competitions, custom...)
+	}
+
+	registry := make(map[string]*Competition, len(competitions))
+	for _, c := range competitions {
+		c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+		if c.Code == "" {
+			return nil, fmt.Errorf("competitions file %s: competition is missing a code", path)
+		}
+		if c.Name == "" {
+			c.Name = c.Code
+		}
+		if c.Provider == "" {
+			c.Provider = "football-data"
+		}
+		for _, p := range []string{c.Provider, c.Fallback} {
+			if p != "" && !footballProviders[p] {
+				return nil, fmt.Errorf("competition %s: unknown provider %q", c.Code, p)
+			}
+			if p == "thesportsdb" && c.SportsDBID == "" {
+				return nil, fmt.Errorf("competition %s: thesportsdb needs a thesportsdb_id", c.Code)
+			}
+		}
+		if c.Fallback == c.Provider {
+			c.Fallback = ""
+		}
+		if c.Timezone != "" {
+			if _, err := time.LoadLocation(c.Timezone); err != nil {
+				return nil, fmt.Errorf("competition %s: %v", c.Code, err)
+			}
+		}
+		registry[c.Code] = c
+	}
+	return registry, nil
+}
+
+// resolveCompetitions looks up the topic's competitions in the registry and
+// fills in the display name, hashtags and timezone the topic leaves unset.
+func (t *Topic) resolveCompetitions(registry map[string]*Competition) error {
+	for i, code := range t.Leagues {
+		c, ok := registry[strings.ToUpper(code)]
+		if !ok {
+			return fmt.Errorf("topic %s: unknown competition %q; add it to COMPETITIONS_FILE", t.Name, code)
+		}
+		t.Leagues[i] = c.Code
+	}
+	if t.League == "" {
+		return nil
+	}
+	c, ok := registry[strings.ToUpper(t.League)]
+	if !ok {
+		return fmt.Errorf("topic %s: unknown competition %q; add it to COMPETITIONS_FILE", t.Name, t.League)
+	}
+	t.League = c.Code
+	t.competition = c
+	if t.DisplayName == "" {
+		t.DisplayName = c.Name
+	}
+	if len(t.Hashtags) == 0 {
+		t.Hashtags = c.Hashtags
+	}
+	if t.Timezone == "" {
+		t.Timezone = c.Timezone
+	}
+	return nil
+}
+
+// checkCompetitions compares the competitions the bot follows against those
+// available to the football-data.org API key. Competitions the key can't
+// access switch to their fallback provider; topics and live competitions left
+// without a source are dropped so they don't fail on every run. It only warns
+// when the list can't be fetched.
+func (nb *NewsBot) checkCompetitions(ctx context.Context) error {
+	var resp struct {
+		Competitions []struct {
+			Code string `json:"code"`
+			Name string `json:"name"`
+		} `json:"competitions"`
+	}
+	if err := nb.footballData(ctx, "/competitions", &resp); err != nil {
+		log.Printf("Warning: couldn't check competitions against football-data.org: %v", err)
+		return nil
+	}
+	available := make(map[string]bool, len(resp.Competitions))
+	for _, c := range resp.Competitions {
+		available[c.Code] = true
+	}
+	for _, c := range nb.competitions {
+		if c.Provider == "football-data" && c.Fallback != "" && !available[c.Code] {
+			log.Printf("%s is not available with this FOOTBALL_DATA_API_KEY, using %s", c.Code, c.Fallback)
+			c.Provider, c.Fallback = c.Fallback, ""
+		}
+	}
+	usable := func(code string, needsFootballData bool) bool {
+		if c := nb.competitions[code]; c != nil && c.Provider != "football-data" && !needsFootballData {
+			return true
+		}
+		return available[code]
+	}
+
+	var topics []*Topic
+	for _, t := range nb.topics {
+		// Scorers and matchdays only come from football-data.org.
+		needsFootballData := t.Kind == "scorers" || t.Kind == "milestones" || t.Kind == "scorers-compare" || t.Kind == "roundup"
+		var missing []string
+		for _, code := range append([]string{t.League}, t.Leagues...) {
+			if code != "" && !usable(code, needsFootballData) {
+				missing = append(missing, code)
+			}
+		}
+		if len(missing) > 0 {
+			log.Printf("Warning: disabling topic %s: %s not available with this FOOTBALL_DATA_API_KEY",
+				t.Name, strings.Join(missing, ", "))
+			continue
+		}
+		topics = append(topics, t)
+	}
+	if len(topics) == 0 {
+		return fmt.Errorf("none of the configured topics' competitions are available with this FOOTBALL_DATA_API_KEY")
+	}
+	nb.topics = topics
+
+	var live []string
+	for _, code := range nb.config.LiveCompetitions {
+		if !usable(code, true) {
+			log.Printf("Warning: not following %s live: not available with this FOOTBALL_DATA_API_KEY", code)
+			continue
+		}
+		live = append(live, code)
+	}
+	nb.config.LiveCompetitions = live
+	return nil
+}
+
+// language returns the language the topic's posts are written in, or ""
+// for the default, English.
+func (t *Topic) language() string {
+	if t == nil || t.competition == nil {
+		return ""
+	}
+	return t.competition.Language
+}
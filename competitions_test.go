@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCompetitions(t *testing.T) {
+	dir := t.TempDir()
+	builtIn, err := loadCompetitions(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if c := builtIn["PL"]; c == nil || c.Name != "Premier League" {
+		t.Errorf("built-in PL = %+v", c)
+	}
+
+	path := filepath.Join(dir, "competitions.json")
+	custom := `[{"code": " pl ", "name": "EPL", "hashtags": ["#EPL"]}, {"code": "MLS", "timezone": "America/New_York"}]`
+	if err := os.WriteFile(path, []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	registry, err := loadCompetitions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := registry["PL"]; c == nil || c.Name != "EPL" || c.Timezone != "" {
+		t.Errorf("replaced PL = %+v", c)
+	}
+	if c := registry["MLS"]; c == nil || c.Name != "MLS" {
+		t.Errorf("added MLS = %+v", c)
+	}
+	if registry["BL1"] == nil {
+		t.Error("built-in BL1 missing after loading a file")
+	}
+}
+
+func TestLoadCompetitionsInvalid(t *testing.T) {
+	for _, custom := range []string{
+		`{"code": "PL"}`,
+		`[{"name": "No code"}]`,
+		`[{"code": "MLS", "timezone": "America/Nowhere"}]`,
+	} {
+		path := filepath.Join(t.TempDir(), "competitions.json")
+		if err := os.WriteFile(path, []byte(custom), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadCompetitions(path); err == nil {
+			t.Errorf("accepted %s", custom)
+		}
+	}
+}
+
+func TestResolveCompetitions(t *testing.T) {
+	registry, err := loadCompetitions(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic := &Topic{Name: "liga", Kind: "league", League: "pd"}
+	if err := topic.resolveCompetitions(registry); err != nil {
+		t.Fatal(err)
+	}
+	if topic.League != "PD" || topic.DisplayName != "La Liga" || topic.Timezone != "Europe/Madrid" || topic.hashtags() != "#LaLiga #Football" {
+		t.Errorf("resolved topic = %+v", topic)
+	}
+
+	if err := (&Topic{Name: "mls", League: "MLS"}).resolveCompetitions(registry); err == nil {
+		t.Error("resolved a competition that isn't in the registry")
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * * funday",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// 2025-03-03 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"0 8,13,18,20 * * *", at(3, 13, 0), true},
+		{"0 8,13,18,20 * * *", at(3, 14, 0), false},
+		{"0 8,13,18,20 * * *", at(3, 13, 1), false},
+		{"*/15 * * * *", at(3, 9, 45), true},
+		{"*/15 * * * *", at(3, 9, 50), false},
+		{"5/20 * * * *", at(3, 9, 45), true},
+		{"5/20 * * * *", at(3, 9, 40), false},
+		{"0 9-17/4 * * *", at(3, 13, 0), true},
+		{"0 9-17/4 * * *", at(3, 15, 0), false},
+		{"* * * * mon-fri", at(3, 12, 0), true},
+		{"* * * * mon-fri", at(8, 12, 0), false}, // Saturday
+		{"* * * * sat,sun", at(9, 12, 0), true},
+		{"* * * * 7", at(9, 12, 0), true}, // 7 is Sunday too
+		{"* * * mar *", at(3, 12, 0), true},
+		{"* * * jan-feb *", at(3, 12, 0), false},
+		{"@hourly", at(3, 12, 0), true},
+		{"@hourly", at(3, 12, 30), false},
+		{"@daily", at(3, 0, 0), true},
+		// With both day fields restricted, either may match.
+		{"0 0 15 * mon", at(3, 0, 0), true},
+		{"0 0 3 * fri", at(3, 0, 0), true},
+		{"0 0 15 * fri", at(3, 0, 0), false},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Matches(tt.t); got != tt.want {
+			t.Errorf("%q.Matches(%s) = %v, want %v", tt.expr, tt.t.Format("Mon 2 Jan 15:04"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// FootballDataSource supplies match data for a competition, normalised to the
+// football-data.org models the rest of the bot works with.
+type FootballDataSource interface {
+	// Results returns the competition's finished matches kicking off
+	// between from and to.
+	Results(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error)
+	// Fixtures returns the competition's scheduled matches kicking off
+	// between from and to.
+	Fixtures(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error)
+	// Standings returns the competition's overall league table.
+	Standings(ctx context.Context, c *Competition) (*LeagueTable, error)
+	// TeamResults returns up to limit of a team's latest finished matches
+	// across all competitions, oldest first.
+	TeamResults(ctx context.Context, teamID, limit int) ([]PremierLeagueMatch, error)
+}
+
+// footballDataSource serves competitions from football-data.org v4.
+type footballDataSource struct {
+	nb *NewsBot
+}
+
+func (s *footballDataSource) Results(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error) {
+	return s.matches(ctx, c, "FINISHED", from, to)
+}
+
+func (s *footballDataSource) Fixtures(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error) {
+	return s.matches(ctx, c, "SCHEDULED,TIMED", from, to)
+}
+
+// matches queries the competition's matches with the given statuses. The API
+// filters by whole days, so the times are narrowed down afterwards.
+func (s *footballDataSource) matches(ctx context.Context, c *Competition, status string, from, to time.Time) ([]PremierLeagueMatch, error) {
+	var resp PremierLeagueMatchesResponse
+	path := fmt.Sprintf("/competitions/%s/matches?status=%s&dateFrom=%s&dateTo=%s",
+		c.Code, status, from.UTC().Format("2006-01-02"), to.UTC().Format("2006-01-02"))
+	if err := s.nb.footballData(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return matchesBetween(resp.Matches, from, to), nil
+}
+
+func (s *footballDataSource) Standings(ctx context.Context, c *Competition) (*LeagueTable, error) {
+	var resp StandingsResponse
+	if err := s.nb.footballData(ctx, fmt.Sprintf("/competitions/%s/standings", c.Code), &resp); err != nil {
+		return nil, err
+	}
+	for _, st := range resp.Standings {
+		if st.Type == "TOTAL" && st.Group == "" && len(st.Table) > 0 {
+			return &LeagueTable{
+				Code:     c.Code,
+				Name:     resp.Competition.Name,
+				Matchday: resp.Season.CurrentMatchday,
+				Rows:     st.Table,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no league table for %s", c.Code)
+}
+
+// teamResultsWindow is how far back team form is looked up; long enough to
+// span an international break.
+const teamResultsWindow = 60 * 24 * time.Hour
+
+func (s *footballDataSource) TeamResults(ctx context.Context, teamID, limit int) ([]PremierLeagueMatch, error) {
+	now := time.Now().UTC()
+	var resp PremierLeagueMatchesResponse
+	path := fmt.Sprintf("/teams/%d/matches?status=FINISHED&dateFrom=%s&dateTo=%s", teamID,
+		now.Add(-teamResultsWindow).Format("2006-01-02"), now.Format("2006-01-02"))
+	if err := s.nb.footballData(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return latestFinished(resp.Matches, limit), nil
+}
+
+// matchesBetween returns the matches kicking off between from and to.
+func matchesBetween(matches []PremierLeagueMatch, from, to time.Time) []PremierLeagueMatch {
+	var out []PremierLeagueMatch
+	for _, m := range matches {
+		if !m.UtcDate.Before(from) && !m.UtcDate.After(to) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// sortByKickoff orders matches by kick-off time, earliest first.
+func sortByKickoff(matches []PremierLeagueMatch) {
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].UtcDate.Before(matches[j].UtcDate) })
+}
+
+// latestFinished returns up to limit of the most recent finished matches,
+// oldest first.
+func latestFinished(matches []PremierLeagueMatch, limit int) []PremierLeagueMatch {
+	var finished []PremierLeagueMatch
+	for _, m := range matches {
+		if m.Status == "FINISHED" {
+			finished = append(finished, m)
+		}
+	}
+	sortByKickoff(finished)
+	return finished[max(0, len(finished)-limit):]
+}
+
+// sourceFor returns the data source registered under provider, where ""
+// means football-data.org.
+func (nb *NewsBot) sourceFor(provider string) FootballDataSource {
+	if provider == "" {
+		provider = "football-data"
+	}
+	return nb.sources[provider]
+}
+
+// fromSources calls fetch with the competition's provider and, if that
+// fails, with its fallback provider.
+func fromSources[T any](nb *NewsBot, ctx context.Context, code string, fetch func(FootballDataSource, *Competition) (T, error)) (T, error) {
+	var zero T
+	c, ok := nb.competitions[code]
+	if !ok {
+		return zero, fmt.Errorf("unknown competition %q", code)
+	}
+	providers := []string{c.Provider}
+	if c.Fallback != "" {
+		providers = append(providers, c.Fallback)
+	}
+	var err error
+	for i, provider := range providers {
+		var out T
+		if out, err = fetch(nb.sourceFor(provider), c); err == nil {
+			return out, nil
+		}
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return zero, err
+		}
+		if i < len(providers)-1 {
+			log.Printf("%s data from %s failed, falling back to %s: %v", c.Code, provider, providers[i+1], err)
+		}
+	}
+	return zero, err
+}
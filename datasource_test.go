@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// namedSource is a FootballDataSource known only by name; fromSources
+// tests pass it to fetch functions that never call its methods.
+type namedSource struct {
+	FootballDataSource
+	name string
+}
+
+func TestFromSources(t *testing.T) {
+	nb := &NewsBot{
+		competitions: map[string]*Competition{
+			"PL":  {Code: "PL", Provider: "football-data", Fallback: "thesportsdb"},
+			"IRL": {Code: "IRL", Provider: "thesportsdb"},
+		},
+		sources: map[string]FootballDataSource{
+			"football-data": namedSource{name: "football-data"},
+			"thesportsdb":   namedSource{name: "thesportsdb"},
+		},
+	}
+	tests := []struct {
+		name    string
+		code    string
+		failing map[string]error
+		want    string
+		wantErr string
+		tried   string
+	}{
+		{name: "provider works", code: "PL", want: "football-data", tried: "football-data"},
+		{
+			name:    "falls back",
+			code:    "PL",
+			failing: map[string]error{"football-data": errors.New("quota used up")},
+			want:    "thesportsdb",
+			tried:   "football-data,thesportsdb",
+		},
+		{
+			name:    "both fail",
+			code:    "PL",
+			failing: map[string]error{"football-data": errors.New("quota used up"), "thesportsdb": errors.New("no such league")},
+			wantErr: "no such league",
+			tried:   "football-data,thesportsdb",
+		},
+		{
+			name:    "no fallback",
+			code:    "IRL",
+			failing: map[string]error{"thesportsdb": errors.New("down")},
+			wantErr: "down",
+			tried:   "thesportsdb",
+		},
+		{
+			name:    "cancelled",
+			code:    "PL",
+			failing: map[string]error{"football-data": fmt.Errorf("request failed: %w", context.Canceled)},
+			wantErr: "context canceled",
+			tried:   "football-data",
+		},
+		{name: "unknown competition", code: "XX", wantErr: `unknown competition "XX"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tried []string
+			got, err := fromSources(nb, context.Background(), tt.code, func(s FootballDataSource, c *Competition) (string, error) {
+				name := s.(namedSource).name
+				tried = append(tried, name)
+				if err := tt.failing[name]; err != nil {
+					return "", err
+				}
+				return name, nil
+			})
+			switch {
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case got != tt.want:
+				t.Errorf("served by %s, want %s", got, tt.want)
+			}
+			if strings.Join(tried, ",") != tt.tried {
+				t.Errorf("tried %v, want %s", tried, tt.tried)
+			}
+		})
+	}
+}
+
+func TestMatchesBetween(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC) }
+	matches := []PremierLeagueMatch{{ID: 1, UtcDate: at(1, 12)}, {ID: 2, UtcDate: at(1, 15)}, {ID: 3, UtcDate: at(2, 16)}, {ID: 4, UtcDate: at(3, 20)}}
+	var got []int
+	for _, m := range matchesBetween(matches, at(1, 15), at(2, 16)) {
+		got = append(got, m.ID)
+	}
+	if fmt.Sprint(got) != "[2 3]" {
+		t.Errorf("got matches %v, want [2 3] (both ends included)", got)
+	}
+}
+
+func TestLatestFinished(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2025, time.March, day, 15, 0, 0, 0, time.UTC) }
+	matches := []PremierLeagueMatch{
+		{ID: 3, UtcDate: at(3), Status: "FINISHED"},
+		{ID: 5, UtcDate: at(5), Status: "SCHEDULED"},
+		{ID: 1, UtcDate: at(1), Status: "FINISHED"},
+		{ID: 4, UtcDate: at(4), Status: "FINISHED"},
+		{ID: 2, UtcDate: at(2), Status: "POSTPONED"},
+	}
+	tests := []struct {
+		limit int
+		want  string
+	}{
+		{2, "[3 4]"},
+		{3, "[1 3 4]"},
+		{10, "[1 3 4]"},
+		{0, "[]"},
+	}
+	for _, tt := range tests {
+		var got []int
+		for _, m := range latestFinished(matches, tt.limit) {
+			got = append(got, m.ID)
+		}
+		if got == nil {
+			got = []int{}
+		}
+		if fmt.Sprint(got) != tt.want {
+			t.Errorf("latestFinished(limit %d) = %v, want %s", tt.limit, got, tt.want)
+		}
+	}
+	if matches[0].ID != 3 {
+		t.Error("latestFinished reordered its input")
+	}
+}
+
+func TestResultsFrom(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		since time.Time
+		want  time.Time
+	}{
+		{"never posted", time.Time{}, now.Add(-resultsWindow)},
+		{"posted long ago", now.Add(-30 * 24 * time.Hour), now.Add(-resultsWindow)},
+		{"posted recently", now.Add(-2 * time.Hour), now.Add(-2*time.Hour - matchDuration)},
+	}
+	for _, tt := range tests {
+		if got := resultsFrom(tt.since, now); !got.Equal(tt.want) {
+			t.Errorf("%s: resultsFrom = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// resultsSource serves a fixed list of results.
+type resultsSource struct {
+	FootballDataSource
+	matches []PremierLeagueMatch
+	from    time.Time
+}
+
+func (s *resultsSource) Results(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error) {
+	s.from = from
+	return s.matches, nil
+}
+
+func TestFetchResultsSince(t *testing.T) {
+	now := time.Now().UTC()
+	match := func(name string, ago time.Duration, status string) PremierLeagueMatch {
+		return PremierLeagueMatch{UtcDate: now.Add(-ago), Status: status,
+			HomeTeam: MatchTeam{Name: name + " Home"}, AwayTeam: MatchTeam{Name: name + " Away"}}
+	}
+	source := &resultsSource{matches: []PremierLeagueMatch{
+		match("Late", 3*time.Hour, "FINISHED"),
+		match("Old", 30*time.Hour, "FINISHED"),
+		match("Posted", 4*time.Hour, "FINISHED"),
+		match("Playing", time.Hour, "IN_PLAY"),
+		match("Early", 5*time.Hour, "FINISHED"),
+	}}
+	store, err := OpenStore(t.TempDir() + "/state.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Record(PostRecord{Key: matchKey(&source.matches[2])}); err != nil {
+		t.Fatal(err)
+	}
+	nb := &NewsBot{
+		store:        store,
+		competitions: map[string]*Competition{"PL": {Code: "PL", Provider: "football-data"}},
+		sources:      map[string]FootballDataSource{"football-data": source},
+	}
+
+	got, err := nb.fetchResultsSince(context.Background(), "PL", now.Add(-6*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range got {
+		names = append(names, m.HomeTeam.Name)
+	}
+	if want := "Early Home,Late Home"; strings.Join(names, ",") != want {
+		t.Errorf("got %v, want %s", names, want)
+	}
+	if want := now.Add(-9 * time.Hour); !source.from.Equal(want) {
+		t.Errorf("requested results from %s, want %s", source.from, want)
+	}
+}
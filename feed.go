@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// feedItemMaxAge is how old a feed item may be and still be posted, so a
+// newly added feed doesn't post its back catalogue.
+const feedItemMaxAge = 48 * time.Hour
+
+// rssFeed is an RSS 2.0 document.
+type rssFeed struct {
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"` // dc:date, used by some feeds instead of pubDate
+}
+
+// atomFeed is an Atom 1.0 document.
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+// fetchFeed returns the items of an RSS or Atom feed as articles. The feed is
+// requested conditionally with the ETag and Last-Modified of the previous
+// fetch, and the stored items are reused when it hasn't changed. A dry run
+// leaves the stored state alone.
+func (nb *NewsBot) fetchFeed(ctx context.Context, feedURL string) ([]NewsAPIArticle, error) {
+	cached, haveCache := nb.store.FeedState(feedURL)
+	request, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", "llm-x-integration/1.0 (+feed reader)")
+	request.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+	if haveCache {
+		if cached.ETag != "" {
+			request.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	state := cached
+	state.Articles = append([]NewsAPIArticle(nil), cached.Articles...)
+	modified := resp.StatusCode != http.StatusNotModified || !haveCache
+	if modified {
+		if resp.StatusCode != 200 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return nil, fmt.Errorf("feed %s error (%d): %s", feedURL, resp.StatusCode, string(body))
+		}
+		raw, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+		if err != nil {
+			return nil, err
+		}
+		articles, err := parseFeed(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse feed %s: %v", feedURL, err)
+		}
+		state = FeedState{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Articles:     articles,
+			Backlog:      cached.Backlog,
+		}
+		if !haveCache {
+			// There's no telling a feed's undated items from its back
+			// catalogue on the first fetch, so none of them are posted.
+			state.Backlog = undatedURLs(articles)
+			if n := len(state.Backlog); n > 0 {
+				log.Printf("Feed %s has %d undated items on its first fetch, treating them as old", feedURL, n)
+			}
+		}
+	}
+	dated := dateUndatedItems(state.Articles, cached.Articles, time.Now().UTC())
+	if (modified || dated > 0) && !nb.config.DryRun {
+		state.Backlog = pruneBacklog(state.Backlog, state.Articles)
+		if err := nb.store.SetFeedState(feedURL, state); err != nil {
+			return nil, err
+		}
+	}
+	return withoutBacklog(state.Articles, state.Backlog), nil
+}
+
+// dateUndatedItems gives items without a date the time they were first
+// seen, so they are news once and then age out like dated items. That is
+// their time in the previous fetch, or now for items new since then. It
+// returns how many items were given now.
+func dateUndatedItems(articles, previous []NewsAPIArticle, now time.Time) int {
+	firstSeen := make(map[string]time.Time, len(previous))
+	for _, a := range previous {
+		firstSeen[a.Url] = a.PublishedAt
+	}
+	dated := 0
+	for i := range articles {
+		a := &articles[i]
+		if !a.PublishedAt.IsZero() {
+			continue
+		}
+		if t, ok := firstSeen[a.Url]; ok && !t.IsZero() {
+			a.PublishedAt = t
+		} else {
+			a.PublishedAt = now
+			dated++
+		}
+	}
+	return dated
+}
+
+// undatedURLs returns the URLs of the items without a date.
+func undatedURLs(articles []NewsAPIArticle) []string {
+	var urls []string
+	for _, a := range articles {
+		if a.PublishedAt.IsZero() {
+			urls = append(urls, a.Url)
+		}
+	}
+	return urls
+}
+
+// pruneBacklog drops backlog URLs that are no longer in the feed.
+func pruneBacklog(backlog []string, articles []NewsAPIArticle) []string {
+	inFeed := make(map[string]bool, len(articles))
+	for _, a := range articles {
+		inFeed[a.Url] = true
+	}
+	var kept []string
+	for _, url := range backlog {
+		if inFeed[url] {
+			kept = append(kept, url)
+		}
+	}
+	return kept
+}
+
+// withoutBacklog returns the articles whose URLs aren't in the backlog.
+func withoutBacklog(articles []NewsAPIArticle, backlog []string) []NewsAPIArticle {
+	if len(backlog) == 0 {
+		return articles
+	}
+	skip := make(map[string]bool, len(backlog))
+	for _, url := range backlog {
+		skip[url] = true
+	}
+	var out []NewsAPIArticle
+	for _, a := range articles {
+		if !skip[a.Url] {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// parseFeed decodes an RSS 2.0 or Atom document, telling them apart by the
+// root element.
+func parseFeed(raw []byte) ([]NewsAPIArticle, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := decodeFeed(raw, &root); err != nil {
+		return nil, err
+	}
+	var articles []NewsAPIArticle
+	switch root.XMLName.Local {
+	case "rss":
+		var feed rssFeed
+		if err := decodeFeed(raw, &feed); err != nil {
+			return nil, err
+		}
+		for _, item := range feed.Channel.Items {
+			a := NewsAPIArticle{
+				Title:       feedText(item.Title),
+				Description: feedText(item.Description),
+				Url:         strings.TrimSpace(item.Link),
+				PublishedAt: parseFeedTime(item.PubDate, item.Date),
+			}
+			// A guid is often the permalink when there's no link.
+			if a.Url == "" && strings.HasPrefix(strings.TrimSpace(item.GUID), "http") {
+				a.Url = strings.TrimSpace(item.GUID)
+			}
+			a.Source.Name = feedText(feed.Channel.Title)
+			articles = append(articles, a)
+		}
+	case "feed":
+		var feed atomFeed
+		if err := decodeFeed(raw, &feed); err != nil {
+			return nil, err
+		}
+		for _, entry := range feed.Entries {
+			a := NewsAPIArticle{
+				Title:       feedText(entry.Title),
+				Description: feedText(entry.Summary),
+				PublishedAt: parseFeedTime(entry.Published, entry.Updated),
+			}
+			if a.Description == "" {
+				a.Description = feedText(entry.Content)
+			}
+			for _, link := range entry.Links {
+				if link.Rel == "" || link.Rel == "alternate" {
+					a.Url = strings.TrimSpace(link.Href)
+					break
+				}
+			}
+			a.Source.Name = feedText(feed.Title)
+			articles = append(articles, a)
+		}
+	default:
+		return nil, fmt.Errorf("not an RSS or Atom feed (root element <%s>)", root.XMLName.Local)
+	}
+	return articles, nil
+}
+
+// decodeFeed unmarshals a feed in any encoding it declares that browsers
+// understand, such as ISO-8859-15 or Windows-1251, with labels resolved as
+// the WHATWG Encoding Standard does.
+func decodeFeed(raw []byte, out interface{}) error {
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		enc, err := htmlindex.Get(charset)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported charset %q", charset)
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	return dec.Decode(out)
+}
+
+var (
+	htmlTagRe    = regexp.MustCompile(`<[^>]*>`)
+	whitespaceRe = regexp.MustCompile(`\s+`)
+)
+
+// feedText turns a feed's title or summary, which may hold escaped HTML,
+// into plain text, cut to a length the prompt can use.
+func feedText(s string) string {
+	s = html.UnescapeString(htmlTagRe.ReplaceAllString(s, " "))
+	s = strings.TrimSpace(whitespaceRe.ReplaceAllString(s, " "))
+	if r := []rune(s); len(r) > 500 {
+		s = string(r[:500]) + "…"
+	}
+	return s
+}
+
+// parseFeedTime parses the first of the given dates that is in one of the
+// RFC 822 or RFC 3339 forms feeds use, or returns the zero time.
+func parseFeedTime(values ...string) time.Time {
+	layouts := []string{
+		time.RFC1123Z, time.RFC1123, time.RFC3339,
+		"Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST",
+		"2 Jan 2006 15:04:05 -0700", time.RFC822Z, time.RFC822,
+	}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC()
+			}
+		}
+	}
+	return time.Time{}
+}
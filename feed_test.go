@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseFeedRSS(t *testing.T) {
+	raw := []byte(`<?xml version="1.0"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel>
+<title>Club News</title>
+<item>
+<title>Smith &amp;amp; Jones sign new deals</title>
+<link> https://example.com/a </link>
+<description>&lt;p&gt;Two &lt;b&gt;key&lt;/b&gt; players  commit.&lt;/p&gt;</description>
+<pubDate>Sat, 01 Mar 2025 15:04:05 +0000</pubDate>
+</item>
+<item>
+<title><![CDATA[Preview: <i>Derby</i> day]]></title>
+<guid>https://example.com/b</guid>
+<dc:date>2025-03-02T10:00:00+01:00</dc:date>
+</item>
+<item>
+<title>Undated</title>
+<link>https://example.com/c</link>
+</item>
+</channel>
+</rss>`)
+	articles, err := parseFeed(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("got %d articles, want 3", len(articles))
+	}
+	want := []struct {
+		title, desc, url string
+		published        time.Time
+	}{
+		{"Smith & Jones sign new deals", "Two key players commit.", "https://example.com/a", time.Date(2025, time.March, 1, 15, 4, 5, 0, time.UTC)},
+		{"Preview: Derby day", "", "https://example.com/b", time.Date(2025, time.March, 2, 9, 0, 0, 0, time.UTC)},
+		{"Undated", "", "https://example.com/c", time.Time{}},
+	}
+	for i, w := range want {
+		a := articles[i]
+		if a.Title != w.title || a.Description != w.desc || a.Url != w.url || !a.PublishedAt.Equal(w.published) {
+			t.Errorf("article %d = %q, %q, %q, %s; want %q, %q, %q, %s", i, a.Title, a.Description, a.Url, a.PublishedAt, w.title, w.desc, w.url, w.published)
+		}
+		if a.Source.Name != "Club News" {
+			t.Errorf("article %d source = %q", i, a.Source.Name)
+		}
+	}
+}
+
+func TestParseFeedAtom(t *testing.T) {
+	raw := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<title type="html">F1 &amp;amp; more</title>
+<entry>
+<title>Race report</title>
+<link rel="replies" href="https://example.com/comments"/>
+<link href="https://example.com/race"/>
+<content type="html">&lt;p&gt;Full report&lt;/p&gt;</content>
+<updated>2025-03-16T06:00:00Z</updated>
+</entry>
+</feed>`)
+	articles, err := parseFeed(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("got %d articles, want 1", len(articles))
+	}
+	a := articles[0]
+	if a.Title != "Race report" || a.Description != "Full report" || a.Url != "https://example.com/race" || a.Source.Name != "F1 & more" {
+		t.Errorf("got %q, %q, %q, %q", a.Title, a.Description, a.Url, a.Source.Name)
+	}
+	if want := time.Date(2025, time.March, 16, 6, 0, 0, 0, time.UTC); !a.PublishedAt.Equal(want) {
+		t.Errorf("published %s, want %s", a.PublishedAt, want)
+	}
+}
+
+func TestParseFeedCharsets(t *testing.T) {
+	tests := []struct {
+		charset string
+		title   string
+		want    string
+	}{
+		{"ISO-8859-1", "Caf\xe9 M\xfcller", "Café Müller"},
+		{"windows-1252", "\x93Caf\xe9\x94 \x96 \x80100", "“Café” – €100"},
+		{"iso-8859-15", "\xa450 \xbduvre", "€50 œuvre"},
+		{"windows-1251", "\xcc\xee\xf1\xea\xe2\xe0", "Москва"},
+		{"us-ascii", "Plain title", "Plain title"},
+		{"utf8", "Caf\xc3\xa9", "Café"},
+	}
+	for _, tt := range tests {
+		raw := []byte(`<?xml version="1.0" encoding="` + tt.charset + `"?><rss><channel><item><title>` + tt.title + `</title></item></channel></rss>`)
+		articles, err := parseFeed(raw)
+		if err != nil {
+			t.Errorf("%s: %v", tt.charset, err)
+			continue
+		}
+		if len(articles) != 1 || articles[0].Title != tt.want {
+			t.Errorf("%s: got %+v, want title %q", tt.charset, articles, tt.want)
+		}
+	}
+}
+
+func TestParseFeedNotAFeed(t *testing.T) {
+	if _, err := parseFeed([]byte(`<html><body>Not found</body></html>`)); err == nil {
+		t.Error("parsed an HTML page as a feed")
+	}
+}
+
+func TestParseFeedTime(t *testing.T) {
+	want := time.Date(2025, time.March, 1, 15, 4, 0, 0, time.UTC)
+	for _, v := range []string{
+		"Sat, 01 Mar 2025 15:04:00 +0000", // RFC 1123Z
+		"Sat, 01 Mar 2025 15:04:00 UTC",   // RFC 1123
+		"2025-03-01T16:04:00+01:00",       // RFC 3339
+		"Sat, 1 Mar 2025 15:04:00 +0000",  // single-digit day
+		"Sat, 1 Mar 2025 15:04:00 UTC",
+		"01 Mar 2025 15:04:00 +0000", // no weekday
+		"1 Mar 2025 15:04:00 +0000",
+		"01 Mar 25 15:04 +0000", // RFC 822Z
+		"01 Mar 25 15:04 UTC",   // RFC 822
+		"  2025-03-01T15:04:00Z ",
+	} {
+		if got := parseFeedTime(v); !got.Equal(want) {
+			t.Errorf("parseFeedTime(%q) = %s, want %s", v, got, want)
+		}
+	}
+	if got := parseFeedTime("", "yesterday", "2025-03-01T15:04:00Z"); !got.Equal(want) {
+		t.Errorf("didn't fall back to the last value: %s", got)
+	}
+	if got := parseFeedTime("yesterday"); !got.IsZero() {
+		t.Errorf("parseFeedTime(%q) = %s, want the zero time", "yesterday", got)
+	}
+}
+
+func TestDateUndatedItems(t *testing.T) {
+	now := time.Date(2025, time.March, 2, 12, 0, 0, 0, time.UTC)
+	seen := now.Add(-5 * time.Hour)
+	dated := now.Add(-time.Hour)
+	articles := []NewsAPIArticle{
+		{Url: "https://example.com/dated", PublishedAt: dated},
+		{Url: "https://example.com/seen"},
+		{Url: "https://example.com/new"},
+	}
+	previous := []NewsAPIArticle{{Url: "https://example.com/seen", PublishedAt: seen}}
+
+	if n := dateUndatedItems(articles, previous, now); n != 1 {
+		t.Errorf("returned %d, want 1", n)
+	}
+	for i, want := range []time.Time{dated, seen, now} {
+		if !articles[i].PublishedAt.Equal(want) {
+			t.Errorf("%s dated %s, want %s", articles[i].Url, articles[i].PublishedAt, want)
+		}
+	}
+}
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Club News</title>
+<item><title>Squad named</title><link>https://example.com/a</link><pubDate>Sat, 01 Mar 2025 15:04:05 +0000</pubDate></item>
+</channel></rss>`
+
+func TestFetchFeedDryRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, testRSS)
+	}))
+	defer srv.Close()
+	store, err := OpenStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nb := &NewsBot{config: &Config{DryRun: true}, store: store}
+	articles, err := nb.fetchFeed(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("got %d articles, want 1", len(articles))
+	}
+	if _, ok := store.FeedState(srv.URL); ok {
+		t.Error("dry run stored the feed state")
+	}
+
+	nb.config.DryRun = false
+	if _, err := nb.fetchFeed(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if state, ok := store.FeedState(srv.URL); !ok || state.ETag != `"v1"` {
+		t.Errorf("feed state = %+v, %v; want the ETag stored", state, ok)
+	}
+}
+
+func TestFetchFeedUndatedItems(t *testing.T) {
+	items := `<item><title>Old news</title><link>https://example.com/old</link></item>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"%d"`, len(items))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, `<rss><channel><title>Club News</title>`+items+`</channel></rss>`)
+	}))
+	defer srv.Close()
+	store, err := OpenStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb := &NewsBot{config: &Config{}, store: store}
+	fetch := func() []NewsAPIArticle {
+		t.Helper()
+		articles, err := nb.fetchFeed(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return articles
+	}
+
+	// The first fetch dates the undated item but holds it back as old.
+	if got := fetch(); len(got) != 0 {
+		t.Errorf("first fetch returned %+v, want nothing", got)
+	}
+	state, _ := store.FeedState(srv.URL)
+	if len(state.Articles) != 1 || state.Articles[0].PublishedAt.IsZero() {
+		t.Errorf("stored %+v, want the item with its first-seen time", state.Articles)
+	}
+
+	// Unchanged, the feed is served from the stored state.
+	if got := fetch(); len(got) != 0 {
+		t.Errorf("not-modified fetch returned %+v, want nothing", got)
+	}
+
+	// An undated item added later is news from when it was first seen.
+	items += `<item><title>Transfer</title><link>https://example.com/new</link></item>`
+	got := fetch()
+	if len(got) != 1 || got[0].Url != "https://example.com/new" || time.Since(got[0].PublishedAt) > time.Minute {
+		t.Errorf("fetch after an update returned %+v, want the new item dated now", got)
+	}
+	firstSeen := got[0].PublishedAt
+	if got := fetch(); len(got) != 1 || !got[0].PublishedAt.Equal(firstSeen) {
+		t.Errorf("not-modified fetch returned %+v, want the new item first seen at %s", got, firstSeen)
+	}
+}
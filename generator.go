@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// GenerateOptions tunes a single text generation request.
+type GenerateOptions struct {
+	SystemPrompt string
+	Temperature  float32
+	MaxTokens    int
+	TopP         float32
+}
+
+// Usage reports the token accounting returned by a provider.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Generation is the text produced by a provider plus metadata about the call.
+type Generation struct {
+	Text     string
+	Provider string
+	Model    string
+	Usage    Usage
+}
+
+// Generator is a text-generation backend (Gemini, Perplexity, a local model, ...).
+type Generator interface {
+	Name() string
+	Generate(ctx context.Context, prompt string, opts GenerateOptions) (*Generation, error)
+}
+
+// GeminiGenerator generates text with the Google Gemini API.
+type GeminiGenerator struct {
+	client *genai.Client
+	model  string
+}
+
+func NewGeminiGenerator(client *genai.Client, model string) *GeminiGenerator {
+	return &GeminiGenerator{client: client, model: model}
+}
+
+func (g *GeminiGenerator) Name() string { return "gemini" }
+
+func (g *GeminiGenerator) Generate(ctx context.Context, prompt string, opts GenerateOptions) (*Generation, error) {
+	model := g.client.GenerativeModel(g.model)
+	model.SetTemperature(opts.Temperature)
+	model.SetMaxOutputTokens(int32(opts.MaxTokens))
+	if opts.TopP > 0 {
+		model.SetTopP(opts.TopP)
+	}
+	if opts.SystemPrompt != "" {
+		model.SystemInstruction = &genai.Content{Parts: []genai.Part{genai.Text(opts.SystemPrompt)}}
+	}
+
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API error: %v", err)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("no content generated by Gemini")
+	}
+
+	var sb strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if text, ok := part.(genai.Text); ok {
+			sb.WriteString(string(text))
+		}
+	}
+
+	gen := &Generation{Text: sb.String(), Provider: g.Name(), Model: g.model}
+	if resp.UsageMetadata != nil {
+		gen.Usage = Usage{
+			PromptTokens:     int(resp.UsageMetadata.PromptTokenCount),
+			CompletionTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+			TotalTokens:      int(resp.UsageMetadata.TotalTokenCount),
+		}
+	}
+	return gen, nil
+}
+
+// OpenAIGenerator generates text with any API that speaks the OpenAI
+// /chat/completions wire format: Perplexity, OpenAI itself, or a self-hosted
+// server such as Ollama, vLLM or llama.cpp.
+type OpenAIGenerator struct {
+	name    string
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIGenerator creates a chat-completions generator. baseURL is the API
+// root without the trailing /chat/completions, e.g. http://localhost:11434/v1.
+// An empty apiKey sends no Authorization header, which local servers accept.
+func NewOpenAIGenerator(name, baseURL, apiKey, model string, timeout time.Duration) *OpenAIGenerator {
+	return &OpenAIGenerator{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func NewPerplexityGenerator(apiKey, model string) *OpenAIGenerator {
+	return NewOpenAIGenerator("perplexity", "https://api.perplexity.ai", apiKey, model, 15*time.Second)
+}
+
+func (o *OpenAIGenerator) Name() string { return o.name }
+
+func (o *OpenAIGenerator) Generate(ctx context.Context, prompt string, opts GenerateOptions) (*Generation, error) {
+	url := o.baseURL + "/chat/completions"
+
+	messages := []map[string]string{}
+	if opts.SystemPrompt != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": opts.SystemPrompt})
+	}
+	messages = append(messages, map[string]string{"role": "user", "content": prompt})
+
+	payload := map[string]interface{}{
+		"model":       o.model,
+		"messages":    messages,
+		"max_tokens":  opts.MaxTokens,
+		"temperature": opts.Temperature,
+	}
+	if opts.TopP > 0 {
+		payload["top_p"] = opts.TopP
+	}
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("accept", "application/json")
+	req.Header.Set("content-type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s API: %v", o.name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s API error (status %d): %s", o.name, resp.StatusCode, string(body))
+	}
+	var result struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage Usage `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %v", o.name, err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("no choices returned from %s", o.name)
+	}
+
+	model := result.Model
+	if model == "" {
+		model = o.model
+	}
+	return &Generation{
+		Text:     result.Choices[0].Message.Content,
+		Provider: o.name,
+		Model:    model,
+		Usage:    result.Usage,
+	}, nil
+}
+
+// FallbackGenerator tries each generator in order and returns the first
+// non-empty result.
+type FallbackGenerator struct {
+	generators []Generator
+}
+
+func NewFallbackGenerator(generators ...Generator) *FallbackGenerator {
+	return &FallbackGenerator{generators: generators}
+}
+
+func (f *FallbackGenerator) Name() string {
+	names := make([]string, len(f.generators))
+	for i, g := range f.generators {
+		names[i] = g.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *FallbackGenerator) Generate(ctx context.Context, prompt string, opts GenerateOptions) (*Generation, error) {
+	if len(f.generators) == 0 {
+		return nil, fmt.Errorf("no generators configured")
+	}
+
+	var failures []string
+	for i, g := range f.generators {
+		gen, err := g.Generate(ctx, prompt, opts)
+		if err == nil && strings.TrimSpace(gen.Text) == "" {
+			err = fmt.Errorf("empty response")
+		}
+		if err == nil {
+			return gen, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", g.Name(), err))
+		if i < len(f.generators)-1 {
+			log.Printf("%s generator failed (%v), falling back to %s...", g.Name(), err, f.generators[i+1].Name())
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("all generators failed: %s", strings.Join(failures, "; "))
+}
+
+// buildGenerators constructs a fallback chain from the named providers, e.g.
+// GENERATOR_CHAIN or a topic's generators. Providers whose credentials are
+// missing are skipped with a warning.
+func buildGenerators(config *Config, geminiClient *genai.Client, names []string) (*FallbackGenerator, error) {
+	var chain []Generator
+	for _, name := range names {
+		switch name {
+		case "gemini":
+			if geminiClient == nil {
+				log.Println("Skipping gemini generator: GOOGLE_API_KEY not set")
+				continue
+			}
+			chain = append(chain, NewGeminiGenerator(geminiClient, config.GeminiModel))
+		case "perplexity":
+			if config.PerplexityAPIKey == "" {
+				log.Println("Skipping perplexity generator: PERPLEXITY_API_KEY not set")
+				continue
+			}
+			chain = append(chain, NewPerplexityGenerator(config.PerplexityAPIKey, config.PerplexityModel))
+		case "openai":
+			if config.OpenAIModel == "" {
+				log.Println("Skipping openai generator: OPENAI_MODEL not set")
+				continue
+			}
+			chain = append(chain, NewOpenAIGenerator("openai", config.OpenAIBaseURL, config.OpenAIAPIKey, config.OpenAIModel, config.OpenAITimeout))
+		default:
+			return nil, fmt.Errorf("unknown generator %q", name)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no usable generators in %v", names)
+	}
+	return NewFallbackGenerator(chain...), nil
+}
+
+// finishTweet normalises raw model output into post text of at most limit
+// weighted characters.
+func finishTweet(content string, limit int) string {
+	content = strings.TrimSpace(content)
+	content = strings.Trim(content, "\"")
+	content = cleanPerplexityTweet(content)
+	return truncateTweet(content, limit)
+}
+
+// maxTweetLength is X's limit for a single post.
+const maxTweetLength = 280
+
+// validateTweet checks that text can be posted within limit weighted
+// characters, counting characters the way X does.
+func validateTweet(text string, limit int) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("text is empty")
+	}
+	if !utf8.ValidString(text) {
+		return fmt.Errorf("text is not valid UTF-8")
+	}
+	if n := tweetWeightedLength(text); n > limit {
+		return fmt.Errorf("text is %d weighted characters, over the %d limit", n, limit)
+	}
+	return nil
+}
+
+var perplexityCitationRe = regexp.MustCompile(`\s*\(\d+\s*chars\)\s*(\[\d+\])*\s*$`)
+
+func cleanPerplexityTweet(content string) string {
+	content = strings.TrimSpace(content)
+	content = perplexityCitationRe.ReplaceAllString(content, "")
+	return strings.TrimSpace(content)
+}
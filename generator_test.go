@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGenerator returns a canned result and counts its calls.
+type fakeGenerator struct {
+	name  string
+	text  string
+	err   error
+	calls int
+}
+
+func (g *fakeGenerator) Name() string { return g.name }
+
+func (g *fakeGenerator) Generate(ctx context.Context, prompt string, opts GenerateOptions) (*Generation, error) {
+	g.calls++
+	if g.err != nil {
+		return nil, g.err
+	}
+	return &Generation{Text: g.text, Provider: g.name}, nil
+}
+
+func TestFallbackGenerator(t *testing.T) {
+	tests := []struct {
+		name      string
+		chain     []*fakeGenerator
+		want      string // provider of the result, "" for an error
+		wantErr   string
+		wantCalls []int
+	}{
+		{
+			name:      "first succeeds",
+			chain:     []*fakeGenerator{{name: "a", text: "hello"}, {name: "b", text: "hi"}},
+			want:      "a",
+			wantCalls: []int{1, 0},
+		},
+		{
+			name:      "falls back on error",
+			chain:     []*fakeGenerator{{name: "a", err: errors.New("quota exceeded")}, {name: "b", text: "hi"}},
+			want:      "b",
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "falls back on empty text",
+			chain:     []*fakeGenerator{{name: "a", text: "  \n"}, {name: "b", text: "hi"}},
+			want:      "b",
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:      "all fail",
+			chain:     []*fakeGenerator{{name: "a", err: errors.New("quota exceeded")}, {name: "b", text: ""}},
+			wantErr:   "all generators failed: a: quota exceeded; b: empty response",
+			wantCalls: []int{1, 1},
+		},
+		{
+			name:    "none configured",
+			wantErr: "no generators configured",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var chain []Generator
+			for _, g := range tt.chain {
+				chain = append(chain, g)
+			}
+			gen, err := NewFallbackGenerator(chain...).Generate(context.Background(), "prompt", GenerateOptions{})
+			switch {
+			case tt.wantErr != "":
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case gen.Provider != tt.want:
+				t.Errorf("result from %s, want %s", gen.Provider, tt.want)
+			}
+			for i, g := range tt.chain {
+				if g.calls != tt.wantCalls[i] {
+					t.Errorf("%s called %d times, want %d", g.name, g.calls, tt.wantCalls[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFallbackGeneratorStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a := &fakeGenerator{name: "a", err: context.Canceled}
+	b := &fakeGenerator{name: "b", text: "hi"}
+	if _, err := NewFallbackGenerator(a, b).Generate(ctx, "prompt", GenerateOptions{}); err == nil {
+		t.Fatal("succeeded after the context was cancelled")
+	}
+	if b.calls != 0 {
+		t.Error("fell back after the context was cancelled")
+	}
+}
+
+func TestFallbackGeneratorName(t *testing.T) {
+	g := NewFallbackGenerator(&fakeGenerator{name: "gemini"}, &fakeGenerator{name: "perplexity"})
+	if got := g.Name(); got != "gemini,perplexity" {
+		t.Errorf("Name() = %q", got)
+	}
+}
+
+func TestCleanPerplexityTweet(t *testing.T) {
+	for in, want := range map[string]string{
+		"Big win for Arsenal! #AFC (120 chars) [1][2]": "Big win for Arsenal! #AFC",
+		"  Plain tweet  ":         "Plain tweet",
+		"Score 2-1 (late winner)": "Score 2-1 (late winner)",
+	} {
+		if got := cleanPerplexityTweet(in); got != want {
+			t.Errorf("cleanPerplexityTweet(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOpenAIGenerator(t *testing.T) {
+	var got struct {
+		auth string
+		body struct {
+			Model       string              `json:"model"`
+			Messages    []map[string]string `json:"messages"`
+			MaxTokens   int                 `json:"max_tokens"`
+			Temperature float32             `json:"temperature"`
+			TopP        *float32            `json:"top_p"`
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v1/chat/completions" {
+			http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		got.auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got.body)
+		w.Write([]byte(`{"choices":[{"message":{"content":"Full time at Molineux"}}],"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17}}`))
+	}))
+	defer srv.Close()
+
+	g := NewOpenAIGenerator("local", srv.URL+"/v1/", "", "llama3", 5*time.Second)
+	gen, err := g.Generate(context.Background(), "Write a tweet", GenerateOptions{SystemPrompt: "You are a reporter", Temperature: 0.7, MaxTokens: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gen.Text != "Full time at Molineux" || gen.Provider != "local" || gen.Model != "llama3" || gen.Usage.TotalTokens != 17 {
+		t.Errorf("got %+v", gen)
+	}
+	if got.auth != "" {
+		t.Errorf("sent Authorization %q without an API key", got.auth)
+	}
+	b := got.body
+	if b.Model != "llama3" || b.MaxTokens != 200 || b.Temperature != 0.7 || b.TopP != nil {
+		t.Errorf("request body %+v", b)
+	}
+	if len(b.Messages) != 2 || b.Messages[0]["role"] != "system" || b.Messages[1]["role"] != "user" || b.Messages[1]["content"] != "Write a tweet" {
+		t.Errorf("messages %v", b.Messages)
+	}
+
+	g = NewOpenAIGenerator("openai", srv.URL+"/v1", "sk-test", "gpt-4o-mini", 5*time.Second)
+	if _, err := g.Generate(context.Background(), "Write a tweet", GenerateOptions{TopP: 0.9}); err != nil {
+		t.Fatal(err)
+	}
+	if got.auth != "Bearer sk-test" {
+		t.Errorf("Authorization = %q", got.auth)
+	}
+	if len(got.body.Messages) != 1 || got.body.TopP == nil || *got.body.TopP != 0.9 {
+		t.Errorf("request body %+v", got.body)
+	}
+}
+
+func TestOpenAIGeneratorErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"server error", http.StatusInternalServerError, `{"error":"model not loaded"}`, "local API error (status 500)"},
+		{"no choices", http.StatusOK, `{"choices":[]}`, "no choices returned from local"},
+		{"bad JSON", http.StatusOK, `<html>`, "failed to decode local response"},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		_, err := NewOpenAIGenerator("local", srv.URL, "", "llama3", 5*time.Second).Generate(context.Background(), "hi", GenerateOptions{})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+		srv.Close()
+	}
+}
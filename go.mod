@@ -6,6 +6,7 @@ require (
 	github.com/dghubble/oauth1 v0.7.2
 	github.com/google/generative-ai-go v0.15.0
 	github.com/joho/godotenv v1.5.1
+	golang.org/x/text v0.15.0
 	google.golang.org/api v0.183.0
 )
 
@@ -36,7 +37,6 @@ require (
 	golang.org/x/oauth2 v0.21.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
-	golang.org/x/text v0.15.0 // indirect
 	golang.org/x/time v0.5.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20240604185151-ef581f913117 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
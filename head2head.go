@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// head2headLimit is how many previous meetings are looked at.
+const head2headLimit = 10
+
+// fetchHead2Head returns the previous meetings between the match's teams,
+// oldest first. Only football-data.org has head-to-head records.
+func (nb *NewsBot) fetchHead2Head(ctx context.Context, match *PremierLeagueMatch) ([]PremierLeagueMatch, error) {
+	if match.Provider != "" {
+		return nil, nil
+	}
+	var resp PremierLeagueMatchesResponse
+	if err := nb.footballData(ctx, fmt.Sprintf("/matches/%d/head2head?limit=%d", match.ID, head2headLimit), &resp); err != nil {
+		return nil, err
+	}
+	var meetings []PremierLeagueMatch
+	for _, m := range resp.Matches {
+		if m.ID != match.ID && m.UtcDate.Before(match.UtcDate) {
+			meetings = append(meetings, m)
+		}
+	}
+	return latestFinished(meetings, head2headLimit), nil
+}
+
+// formStreak describes the run a form string such as "LWWWW" ends on, e.g.
+// "have won their last 4", or "" when there's no run of three or more.
+func formStreak(form string) string {
+	run := func(results string) int {
+		n := 0
+		for n < len(form) && strings.IndexByte(results, form[len(form)-1-n]) >= 0 {
+			n++
+		}
+		return n
+	}
+	switch {
+	case run("W") >= 3:
+		return fmt.Sprintf("have won their last %d", run("W"))
+	case run("L") >= 3:
+		return fmt.Sprintf("have lost their last %d", run("L"))
+	case run("WD") >= 3:
+		return fmt.Sprintf("are unbeaten in their last %d", run("WD"))
+	case run("DL") >= 3:
+		return fmt.Sprintf("are without a win in their last %d", run("DL"))
+	}
+	return ""
+}
+
+// winnerOf returns the ID of the team that won a finished match, or 0 for a
+// draw.
+func winnerOf(m *PremierLeagueMatch) int {
+	switch {
+	case m.Score.FullTime.Home > m.Score.FullTime.Away:
+		return m.HomeTeam.ID
+	case m.Score.FullTime.Home < m.Score.FullTime.Away:
+		return m.AwayTeam.ID
+	}
+	return 0
+}
+
+// head2HeadFacts summarises the previous meetings. For a finished match it
+// also notes a winner's first win over the opponent in three or more
+// meetings. Scores of past meetings are left out so they can't be mistaken
+// for this match's.
+func head2HeadFacts(match *PremierLeagueMatch, meetings []PremierLeagueMatch) []string {
+	if len(meetings) == 0 {
+		return nil
+	}
+	wins := map[int]int{}
+	for i := range meetings {
+		wins[winnerOf(&meetings[i])]++
+	}
+	home, away := match.HomeTeam, match.AwayTeam
+	facts := []string{fmt.Sprintf("Head-to-head (last %s): %s %s, %s %s, %s",
+		plural(len(meetings), "meeting"), home.Name, plural(wins[home.ID], "win"),
+		away.Name, plural(wins[away.ID], "win"), plural(wins[0], "draw"))}
+
+	last := &meetings[len(meetings)-1]
+	outcome := "draw"
+	switch winnerOf(last) {
+	case home.ID:
+		outcome = home.Name + " won"
+	case away.ID:
+		outcome = away.Name + " won"
+	}
+	facts = append(facts, fmt.Sprintf("Last meeting: %s v %s on %s, %s",
+		last.HomeTeam.Name, last.AwayTeam.Name, last.UtcDate.Format("2 Jan 2006"), outcome))
+
+	if match.Status == "FINISHED" {
+		if winner := winnerOf(match); winner != 0 {
+			winless := 0
+			for i := len(meetings) - 1; i >= 0 && winnerOf(&meetings[i]) != winner; i-- {
+				winless++
+			}
+			if winless >= 2 {
+				winnerTeam, loser := home, away
+				if winner == away.ID {
+					winnerTeam, loser = away, home
+				}
+				facts = append(facts, fmt.Sprintf("This is %s first win over %s in %d meetings",
+					possessive(winnerTeam.Name), loser.Name, winless+1))
+			}
+		}
+	}
+	return facts
+}
+
+// plural formats a count of things, e.g. "1 win" or "2 wins".
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// possessive forms the possessive of a name, e.g. "Arsenal's" or "Wolves'".
+func possessive(name string) string {
+	if strings.HasSuffix(name, "s") {
+		return name + "'"
+	}
+	return name + "'s"
+}
+
+// matchContext gathers each team's recent form and the head-to-head record
+// for a result or preview post. Anything that can't be fetched is left out.
+func (nb *NewsBot) matchContext(ctx context.Context, match *PremierLeagueMatch) string {
+	var lines []string
+	for _, team := range []MatchTeam{match.HomeTeam, match.AwayTeam} {
+		form, err := nb.fetchTeamForm(ctx, match.Provider, team.ID)
+		if err != nil {
+			log.Printf("Failed to fetch form for %s, leaving it out: %v", team.Name, err)
+			continue
+		}
+		if form == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s form (last %d, most recent last): %s", team.Name, len(form), form))
+		if streak := formStreak(form); streak != "" {
+			lines = append(lines, fmt.Sprintf("%s %s", team.Name, streak))
+		}
+	}
+	meetings, err := nb.fetchHead2Head(ctx, match)
+	if err != nil {
+		log.Printf("Failed to fetch head-to-head for match %d, leaving it out: %v", match.ID, err)
+	}
+	lines = append(lines, head2HeadFacts(match, meetings)...)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormStreak(t *testing.T) {
+	tests := []struct{ form, want string }{
+		{"", ""},
+		{"WW", ""},
+		{"LWWWW", "have won their last 4"},
+		{"WLLL", "have lost their last 3"},
+		{"WDWDW", "are unbeaten in their last 5"},
+		{"DDD", "are unbeaten in their last 3"},
+		{"WDLDL", "are without a win in their last 4"},
+		{"WWLDW", ""},
+	}
+	for _, tt := range tests {
+		if got := formStreak(tt.form); got != tt.want {
+			t.Errorf("formStreak(%q) = %q, want %q", tt.form, got, tt.want)
+		}
+	}
+}
+
+func TestHead2HeadFacts(t *testing.T) {
+	wolves := MatchTeam{ID: 76, Name: "Wolves"}
+	liverpool := MatchTeam{ID: 64, Name: "Liverpool"}
+	meeting := func(year int, home, away MatchTeam, hs, as int) PremierLeagueMatch {
+		m := PremierLeagueMatch{HomeTeam: home, AwayTeam: away, Status: "FINISHED",
+			UtcDate: time.Date(year, time.March, 1, 15, 0, 0, 0, time.UTC)}
+		m.Score.FullTime.Home, m.Score.FullTime.Away = hs, as
+		return m
+	}
+	meetings := []PremierLeagueMatch{
+		meeting(2021, liverpool, wolves, 2, 2),
+		meeting(2022, wolves, liverpool, 0, 1),
+		meeting(2023, liverpool, wolves, 3, 0),
+	}
+
+	tests := []struct {
+		name     string
+		match    PremierLeagueMatch
+		meetings []PremierLeagueMatch
+		want     []string
+	}{
+		{"no meetings", meeting(2025, wolves, liverpool, 1, 0), nil, nil},
+		{
+			name:     "preview",
+			match:    PremierLeagueMatch{HomeTeam: wolves, AwayTeam: liverpool, Status: "TIMED"},
+			meetings: meetings,
+			want: []string{
+				"Head-to-head (last 3 meetings): Wolves 0 wins, Liverpool 2 wins, 1 draw",
+				"Last meeting: Liverpool v Wolves on 1 Mar 2023, Liverpool won",
+			},
+		},
+		{
+			name:     "first win in a while",
+			match:    meeting(2025, wolves, liverpool, 2, 1),
+			meetings: meetings,
+			want: []string{
+				"Head-to-head (last 3 meetings): Wolves 0 wins, Liverpool 2 wins, 1 draw",
+				"Last meeting: Liverpool v Wolves on 1 Mar 2023, Liverpool won",
+				"This is Wolves' first win over Liverpool in 4 meetings",
+			},
+		},
+		{
+			name:     "usual winner",
+			match:    meeting(2025, wolves, liverpool, 0, 2),
+			meetings: meetings,
+			want: []string{
+				"Head-to-head (last 3 meetings): Wolves 0 wins, Liverpool 2 wins, 1 draw",
+				"Last meeting: Liverpool v Wolves on 1 Mar 2023, Liverpool won",
+			},
+		},
+		{
+			name:     "one meeting",
+			match:    meeting(2025, wolves, liverpool, 2, 1),
+			meetings: meetings[:1],
+			want: []string{
+				"Head-to-head (last 1 meeting): Wolves 0 wins, Liverpool 0 wins, 1 draw",
+				"Last meeting: Liverpool v Wolves on 1 Mar 2021, draw",
+			},
+		},
+		{
+			name:     "draw",
+			match:    meeting(2025, wolves, liverpool, 1, 1),
+			meetings: meetings[:2],
+			want: []string{
+				"Head-to-head (last 2 meetings): Wolves 0 wins, Liverpool 1 win, 1 draw",
+				"Last meeting: Wolves v Liverpool on 1 Mar 2022, Liverpool won",
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := head2HeadFacts(&tt.match, tt.meetings)
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s:\ngot  %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPossessive(t *testing.T) {
+	for name, want := range map[string]string{"Arsenal": "Arsenal's", "Wolves": "Wolves'", "Spurs": "Spurs'", "Real Madrid": "Real Madrid's"} {
+		if got := possessive(name); got != want {
+			t.Errorf("possessive(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
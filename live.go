@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// liveStaleAfter is how long a tracked match may go unseen before it is
+// dropped, e.g. when it was abandoned or fell outside the polled dates.
+const liveStaleAfter = 6 * time.Hour
+
+// Live polls the configured competitions for matches in play and posts goal,
+// half-time and full-time alerts until ctx is cancelled. The last seen state
+// of each match is persisted so a restart picks up where it left off without
+// repeating alerts.
+func (nb *NewsBot) Live(ctx context.Context) error {
+	competitions := nb.liveCompetitions()
+	if len(competitions) == 0 {
+		return fmt.Errorf("no competitions to follow: set LIVE_COMPETITIONS or configure league topics")
+	}
+	log.Printf("Following live matches in %s every %s", strings.Join(competitions, ", "), nb.config.LivePollInterval)
+
+	for {
+		// Like scheduled jobs, a poll isn't cut short by shutdown so an alert
+		// being posted is recorded.
+		pollCtx, cancel := context.WithTimeout(context.Background(), nb.config.JobTimeout)
+		if err := nb.pollLive(pollCtx, competitions); err != nil {
+			log.Printf("Live poll failed: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			log.Println("Live mode stopped")
+			return nil
+		case <-time.After(nb.config.LivePollInterval):
+		}
+	}
+}
+
+// liveCompetitions returns LIVE_COMPETITIONS, defaulting to the leagues of
+// the configured football topics.
+func (nb *NewsBot) liveCompetitions() []string {
+	if len(nb.config.LiveCompetitions) > 0 {
+		return nb.config.LiveCompetitions
+	}
+	var codes []string
+	seen := make(map[string]bool)
+	for _, t := range nb.topics {
+		if t.League != "" && !seen[t.League] {
+			seen[t.League] = true
+			codes = append(codes, t.League)
+		}
+	}
+	return codes
+}
+
+// pollLive fetches today's matches in one request, to stay within the
+// football-data.org rate limit, and compares each with its last seen state.
+func (nb *NewsBot) pollLive(ctx context.Context, competitions []string) error {
+	now := time.Now().UTC()
+	var resp PremierLeagueMatchesResponse
+	path := fmt.Sprintf("/matches?competitions=%s&dateFrom=%s&dateTo=%s", strings.Join(competitions, ","),
+		now.AddDate(0, 0, -1).Format("2006-01-02"), now.AddDate(0, 0, 1).Format("2006-01-02"))
+	if err := nb.footballData(ctx, path, &resp); err != nil {
+		return err
+	}
+
+	tracked := nb.store.LiveStates()
+	seen := make(map[int]bool)
+	for i := range resp.Matches {
+		m := &resp.Matches[i]
+		seen[m.ID] = true
+		prev, known := tracked[m.ID]
+		if err := nb.updateLiveMatch(ctx, m, prev, known); err != nil {
+			log.Printf("Live update for %s v %s failed: %v", m.HomeTeam.Name, m.AwayTeam.Name, err)
+		}
+	}
+	for id, st := range tracked {
+		if !seen[id] && now.Sub(st.UpdatedAt) > liveStaleAfter {
+			log.Printf("No longer tracking match %d, unseen since %s", id, st.UpdatedAt.Format(time.RFC3339))
+			nb.store.SetLiveState(id, nil)
+		}
+	}
+	return nil
+}
+
+// updateLiveMatch posts any alerts for the change from prev to m and stores
+// the new state. A match seen for the first time only sets the baseline, so
+// starting mid-game doesn't replay goals that were already scored.
+func (nb *NewsBot) updateLiveMatch(ctx context.Context, m *PremierLeagueMatch, prev LiveMatchState, known bool) error {
+	state := &LiveMatchState{
+		Competition: m.Competition.Code,
+		Status:      m.Status,
+		Home:        m.Score.FullTime.Home,
+		Away:        m.Score.FullTime.Away,
+	}
+	state.Goals = goalCount(prev, state.Home, state.Away)
+	switch m.Status {
+	case "IN_PLAY", "PAUSED":
+		if !known {
+			log.Printf("Tracking %s %d-%d %s (%s)", m.HomeTeam.Name, state.Home, state.Away, m.AwayTeam.Name, m.Status)
+			return nb.store.SetLiveState(m.ID, state)
+		}
+		if err := nb.postGoalAlert(ctx, m, prev, state.Goals); err != nil {
+			return err
+		}
+		if m.Status == "PAUSED" && prev.Status == "IN_PLAY" {
+			if err := nb.postLiveAlert(ctx, m, "ht", "⏸️ Half-time"); err != nil {
+				return err
+			}
+		}
+		return nb.store.SetLiveState(m.ID, state)
+	case "FINISHED":
+		if !known {
+			return nil
+		}
+		if err := nb.postGoalAlert(ctx, m, prev, state.Goals); err != nil {
+			return err
+		}
+		if err := nb.postLiveAlert(ctx, m, "ft", "🏁 Full-time"); err != nil {
+			return err
+		}
+		return nb.store.SetLiveState(m.ID, nil)
+	default:
+		if known {
+			log.Printf("Match %d is now %s, no longer tracking it", m.ID, m.Status)
+			return nb.store.SetLiveState(m.ID, nil)
+		}
+		return nil
+	}
+}
+
+// goalCount returns how many goals have been scored in the match so far,
+// counting disallowed ones, given its last state and the current score. It
+// only goes up, so a goal that restores a scoreline after a VAR reversal
+// still gets its own alert.
+func goalCount(prev LiveMatchState, home, away int) int {
+	goals := max(prev.Goals, prev.Home+prev.Away)
+	if home > prev.Home {
+		goals += home - prev.Home
+	}
+	if away > prev.Away {
+		goals += away - prev.Away
+	}
+	return goals
+}
+
+// postGoalAlert posts an alert when the score has gone up since prev. goals
+// is the match's goal count, which keys the alert.
+func (nb *NewsBot) postGoalAlert(ctx context.Context, m *PremierLeagueMatch, prev LiveMatchState, goals int) error {
+	home, away := m.Score.FullTime.Home, m.Score.FullTime.Away
+	event := fmt.Sprintf("goal:%d:%d-%d", goals, home, away)
+	switch {
+	case home < prev.Home || away < prev.Away:
+		log.Printf("Score in %s v %s went from %d-%d to %d-%d, goal disallowed?",
+			m.HomeTeam.Name, m.AwayTeam.Name, prev.Home, prev.Away, home, away)
+		return nil
+	case home > prev.Home && away > prev.Away:
+		return nb.postLiveAlert(ctx, m, event, "⚽ GOALS!")
+	case home > prev.Home:
+		return nb.postLiveAlert(ctx, m, event, fmt.Sprintf("⚽ GOAL for %s!", teamLabel(m.HomeTeam)))
+	case away > prev.Away:
+		return nb.postLiveAlert(ctx, m, event, fmt.Sprintf("⚽ GOAL for %s!", teamLabel(m.AwayTeam)))
+	}
+	return nil
+}
+
+// postLiveAlert publishes a templated alert for the match, once per event.
+func (nb *NewsBot) postLiveAlert(ctx context.Context, m *PremierLeagueMatch, event, headline string) error {
+	key := liveKey(m.ID, event)
+	if nb.store.HasPosted(key) {
+		return nil
+	}
+	text := fmt.Sprintf("%s\n\n%s %d-%d %s\n\n%s",
+		headline, teamLabel(m.HomeTeam), m.Score.FullTime.Home, m.Score.FullTime.Away, teamLabel(m.AwayTeam),
+		nb.leagueHashtags(m.Competition.Code))
+	text = truncateTweet(text, maxTweetLength)
+	log.Printf("Live alert: %s", strings.ReplaceAll(text, "\n", " "))
+
+	topic := "live:" + m.Competition.Code
+	results, err := nb.publish(ctx, &Post{Topic: topic, Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to publish %s alert: %v", event, err)
+	}
+	return nb.recordPost(&Draft{Topic: topic, Text: text, SourceKey: key, Source: m}, results)
+}
+
+// teamLabel is the name used in alerts, preferring the short name.
+func teamLabel(t MatchTeam) string {
+	if t.ShortName != "" {
+		return t.ShortName
+	}
+	return t.Name
+}
+
+// leagueHashtags returns the hashtags of the first topic for the league,
+// falling back to those in the competition registry.
+func (nb *NewsBot) leagueHashtags(code string) string {
+	for _, t := range nb.topics {
+		if t.League == code && len(t.Hashtags) > 0 {
+			return t.hashtags()
+		}
+	}
+	if c := nb.competitions[code]; c != nil && len(c.Hashtags) > 0 {
+		return strings.Join(c.Hashtags, " ")
+	}
+	return "#Football"
+}
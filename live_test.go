@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestGoalCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev       LiveMatchState
+		home, away int
+		want       int
+	}{
+		{"first seen", LiveMatchState{}, 2, 1, 3},
+		{"no change", LiveMatchState{Home: 1, Away: 0, Goals: 1}, 1, 0, 1},
+		{"home goal", LiveMatchState{Home: 1, Away: 0, Goals: 1}, 2, 0, 2},
+		{"goal each", LiveMatchState{Home: 1, Away: 0, Goals: 1}, 2, 1, 3},
+		{"disallowed", LiveMatchState{Home: 1, Away: 0, Goals: 1}, 0, 0, 1},
+		{"restores scoreline after VAR", LiveMatchState{Home: 0, Away: 0, Goals: 1}, 1, 0, 2},
+		{"state saved before goal counts", LiveMatchState{Home: 2, Away: 2}, 3, 2, 5},
+	}
+	for _, tt := range tests {
+		if got := goalCount(tt.prev, tt.home, tt.away); got != tt.want {
+			t.Errorf("%s: goalCount = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,17 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
 	"net/http"
 	"os"
-	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,17 +31,70 @@ type Config struct {
 	FootballDataAPIKey  string
 	NewsAPIKey          string // NEW
 	PerplexityAPIKey    string // NEW
+	GeneratorChain      []string
+	GeminiModel         string
+	PerplexityModel     string
+	OpenAIBaseURL       string
+	OpenAIAPIKey        string
+	OpenAIModel         string
+	OpenAITimeout       time.Duration
+	Publishers          []string
+	MastodonServer      string
+	MastodonAccessToken string
+	BlueskyPDS          string
+	BlueskyHandle       string
+	BlueskyAppPassword  string
+	WebhookURL          string
+	WebhookSecret       string
+	StateFile           string
+	TopicsFile          string
+	CompetitionsFile    string
+	TheSportsDBAPIKey   string
+	Schedule            string
+	JobTimeout          time.Duration
+	DryRun              bool // generate only; no publishers are built and nothing is recorded
+	ThreadMaxPosts      int
+	ThreadRollback      bool
+	ScoreCards          bool   // attach a rendered score card to result posts
+	TeamColoursFile     string // JSON map of team name -> kit colours for score cards
+	BadgeDir            string // directory of <competition code>.png badges
+	VerifyAttempts      int    // generations allowed per post before a draft that contradicts its source is abandoned
+	FootballDataRate    int    // football-data.org requests allowed per minute
+	LiveCompetitions    []string
+	LivePollInterval    time.Duration
+	TeamIDs             []int    // football-data.org team IDs followed by the default team topic
+	TeamName            string   // display name for the default team topic
+	TeamHashtags        []string // hashtags for the default team topic
+	NewsSources         []string // preferred NewsAPI source IDs or names, best first
 }
 
 type NewsBot struct {
 	config       *Config
 	geminiClient *genai.Client
+	generator    Generator
+	publishers   []Publisher
+	store        *Store
+	topics       []*Topic
+	competitions map[string]*Competition
+	sources      map[string]FootballDataSource // by provider name
+	colours      map[string]TeamColours
 	httpClient   *http.Client
+	footballRate *rateLimiter
 }
 
 // X API v2 tweet request structure
 type TweetRequest struct {
-	Text string `json:"text"`
+	Text  string      `json:"text"`
+	Reply *TweetReply `json:"reply,omitempty"`
+	Media *TweetMedia `json:"media,omitempty"`
+}
+
+type TweetMedia struct {
+	MediaIDs []string `json:"media_ids"`
+}
+
+type TweetReply struct {
+	InReplyToTweetID string `json:"in_reply_to_tweet_id"`
 }
 
 // X API v2 tweet response structure
@@ -56,20 +110,50 @@ type TweetResponse struct {
 }
 
 type PremierLeagueMatch struct {
-	HomeTeam struct {
-		Name string `json:"name"`
-	} `json:"homeTeam"`
-	AwayTeam struct {
-		Name string `json:"name"`
-	} `json:"awayTeam"`
-	UtcDate string `json:"utcDate"`
-	Status  string `json:"status"`
-	Score   struct {
+	ID       int       `json:"id"`
+	HomeTeam MatchTeam `json:"homeTeam"`
+	AwayTeam MatchTeam `json:"awayTeam"`
+	UtcDate  time.Time `json:"utcDate"`
+	Venue    string    `json:"venue,omitempty"`
+	Status   string    `json:"status"`
+	Matchday int       `json:"matchday,omitempty"`
+	Provider string    `json:"provider,omitempty"` // data source, "" for football-data.org
+	Score    struct {
+		Winner   string `json:"winner,omitempty"`   // HOME_TEAM, AWAY_TEAM or DRAW
+		Duration string `json:"duration,omitempty"` // REGULAR, EXTRA_TIME or PENALTY_SHOOTOUT
 		FullTime struct {
 			Home int `json:"home"`
 			Away int `json:"away"`
 		} `json:"fullTime"`
+		HalfTime struct {
+			Home int `json:"home"`
+			Away int `json:"away"`
+		} `json:"halfTime"`
+		Penalties struct {
+			Home int `json:"home"`
+			Away int `json:"away"`
+		} `json:"penalties"`
 	} `json:"score"`
+	Competition struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	} `json:"competition"`
+	Referees []Referee `json:"referees,omitempty"`
+
+	// Match events, only sent by the match detail endpoint and only on
+	// football-data.org plans that include them.
+	Goals         []MatchGoal         `json:"goals,omitempty"`
+	Bookings      []MatchBooking      `json:"bookings,omitempty"`
+	Substitutions []MatchSubstitution `json:"substitutions,omitempty"`
+}
+
+type MatchTeam struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	ShortName string        `json:"shortName,omitempty"`
+	TLA       string        `json:"tla,omitempty"`
+	Formation string        `json:"formation,omitempty"`
+	Lineup    []MatchPlayer `json:"lineup,omitempty"` // starting eleven, match detail only
 }
 
 type PremierLeagueMatchesResponse struct {
@@ -77,10 +161,12 @@ type PremierLeagueMatchesResponse struct {
 }
 
 type NewsAPIArticle struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Url         string `json:"url"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Url         string    `json:"url"`
+	PublishedAt time.Time `json:"publishedAt"`
 	Source      struct {
+		ID   string `json:"id"`
 		Name string `json:"name"`
 	} `json:"source"`
 }
@@ -91,6 +177,23 @@ type NewsAPIResponse struct {
 	Articles     []NewsAPIArticle `json:"articles"`
 }
 
+// Draft is generated post text along with the source item it was written
+// about, so the item can be recorded once posted or shown in a dry run.
+type Draft struct {
+	Topic     string
+	Text      string
+	SourceKey string
+	Provider  string
+	Model     string
+	Source    interface{} // the match or article the post was written from
+	Media     []Media
+	Segments  []string // posts of a thread built explicitly, e.g. a roundup; Text then holds the unsplit version
+
+	// onRecorded, if set, runs once the draft has been published and
+	// recorded, e.g. to save the table a standings post was diffed against.
+	onRecorded func() error
+}
+
 func loadConfig() (*Config, error) {
 	godotenv.Load()
 
@@ -104,34 +207,170 @@ func loadConfig() (*Config, error) {
 		FootballDataAPIKey:  os.Getenv("FOOTBALL_DATA_API_KEY"), // NEW
 		NewsAPIKey:          os.Getenv("NEWS_API_KEY"),          // NEW
 		PerplexityAPIKey:    os.Getenv("PERPLEXITY_API_KEY"),    // NEW
+		GeneratorChain:      splitList(strings.ToLower(getEnv("GENERATOR_CHAIN", "gemini,perplexity"))),
+		GeminiModel:         getEnv("GEMINI_MODEL", "gemini-flash-latest"),
+		PerplexityModel:     getEnv("PERPLEXITY_MODEL", "sonar"),
+		OpenAIBaseURL:       getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		OpenAIAPIKey:        os.Getenv("OPENAI_API_KEY"),
+		OpenAIModel:         os.Getenv("OPENAI_MODEL"),
+		Publishers:          splitList(strings.ToLower(getEnv("PUBLISHERS", "x"))),
+		MastodonServer:      os.Getenv("MASTODON_SERVER"),
+		MastodonAccessToken: os.Getenv("MASTODON_ACCESS_TOKEN"),
+		BlueskyPDS:          getEnv("BLUESKY_PDS", "https://bsky.social"),
+		BlueskyHandle:       os.Getenv("BLUESKY_HANDLE"),
+		BlueskyAppPassword:  os.Getenv("BLUESKY_APP_PASSWORD"),
+		WebhookURL:          os.Getenv("WEBHOOK_URL"),
+		WebhookSecret:       os.Getenv("WEBHOOK_SECRET"),
+		StateFile:           getEnv("STATE_FILE", "bot-state.json"),
+		TopicsFile:          getEnv("TOPICS_FILE", "topics.json"),
+		CompetitionsFile:    getEnv("COMPETITIONS_FILE", "competitions.json"),
+		TheSportsDBAPIKey:   getEnv("THESPORTSDB_API_KEY", "123"),
+		Schedule:            getEnv("SCHEDULE", "0 8,13,18,20 * * *"),
+		ThreadRollback:      os.Getenv("THREAD_ROLLBACK") == "true",
+		ScoreCards:          os.Getenv("SCORE_CARDS") != "false",
+		TeamColoursFile:     getEnv("TEAM_COLOURS_FILE", "team-colours.json"),
+		BadgeDir:            getEnv("BADGE_DIR", "badges"),
+		LiveCompetitions:    splitList(strings.ToUpper(os.Getenv("LIVE_COMPETITIONS"))),
+		TeamName:            os.Getenv("TEAM_NAME"),
+		TeamHashtags:        splitList(getEnv("TEAM_HASHTAGS", "#LFC,#Liverpool")),
+		NewsSources:         splitList(strings.ToLower(getEnv("NEWS_SOURCES", "coindesk,cointelegraph,the-block,decrypt,reuters,bloomberg,financial-times,cnbc"))),
+	}
+
+	timeout, err := getEnvDuration("OPENAI_TIMEOUT", 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	config.OpenAITimeout = timeout
+	if config.JobTimeout, err = getEnvDuration("JOB_TIMEOUT", 5*time.Minute); err != nil {
+		return nil, err
+	}
+	if config.ThreadMaxPosts, err = getEnvInt("THREAD_MAX_POSTS", 5); err != nil {
+		return nil, err
+	}
+	if config.VerifyAttempts, err = getEnvInt("VERIFY_ATTEMPTS", 3); err != nil {
+		return nil, err
+	}
+	if config.FootballDataRate, err = getEnvInt("FOOTBALL_DATA_RATE_LIMIT", 10); err != nil {
+		return nil, err
+	}
+	if config.LivePollInterval, err = getEnvDuration("LIVE_POLL_INTERVAL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	for _, id := range splitList(os.Getenv("TEAM_IDS")) {
+		n, err := strconv.Atoi(id)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid TEAM_IDS: %q is not a football-data.org team ID", id)
+		}
+		config.TeamIDs = append(config.TeamIDs, n)
 	}
 
 	if config.LiverpoolNewsPrompt == "" {
 		config.LiverpoolNewsPrompt = "Generate a concise and engaging tweet about Liverpool FC news. Focus on recent matches, transfers, or club updates. Keep it under 280 characters and make it engaging for football fans. Include relevant hashtags like #LFC #Liverpool"
 	}
 
-	if config.GoogleAPIKey == "" {
+	if config.GoogleAPIKey == "" && slices.Contains(config.GeneratorChain, "gemini") {
 		return nil, fmt.Errorf("GOOGLE_API_KEY is required")
 	}
-	if config.XAPIKey == "" || config.XAPIKeySecret == "" ||
-		config.XAccessToken == "" || config.XAccessTokenSecret == "" {
-		return nil, fmt.Errorf("all X API credentials are required")
+	return config, nil
+}
+
+// getEnv returns the value of the environment variable or def when unset.
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// getEnvDuration parses a Go duration (e.g. "90s", "2m") from the environment.
+func getEnvDuration(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", key, err)
 	}
-	if config.FootballDataAPIKey == "" {
-		return nil, fmt.Errorf("FOOTBALL_DATA_API_KEY is required")
+	return d, nil
+}
+
+// getEnvInt parses a positive integer from the environment.
+func getEnvInt(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
 	}
-	if config.NewsAPIKey == "" {
-		return nil, fmt.Errorf("NEWS_API_KEY is required")
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be a positive integer", key)
 	}
+	return n, nil
+}
 
-	return config, nil
+// splitList parses a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
 }
 
 func NewNewsBot(config *Config) (*NewsBot, error) {
 	ctx := context.Background()
-	geminiClient, err := genai.NewClient(ctx, option.WithAPIKey(config.GoogleAPIKey))
+	var geminiClient *genai.Client
+	if config.GoogleAPIKey != "" {
+		var err error
+		geminiClient, err = genai.NewClient(ctx, option.WithAPIKey(config.GoogleAPIKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Gemini client: %v", err)
+		}
+	}
+
+	generator, err := buildGenerators(config, geminiClient, config.GeneratorChain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure generators: %v", err)
+	}
+
+	competitions, err := loadCompetitions(config.CompetitionsFile)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := loadTopics(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Gemini client: %v", err)
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, t := range topics {
+		if t.Kind == "team" && len(t.Teams) == 0 {
+			t.Teams = config.TeamIDs
+		}
+		if err := t.resolveCompetitions(competitions); err != nil {
+			return nil, err
+		}
+		if err := t.validate(); err != nil {
+			return nil, err
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("duplicate topic %q", t.Name)
+		}
+		seen[t.Name] = true
+		if t.Weight == 0 && t.Schedule == "" {
+			log.Printf("Warning: topic %s has weight 0 and no schedule, so it will never run", t.Name)
+		}
+		if len(t.Generators) > 0 {
+			t.generator, err = buildGenerators(config, geminiClient, t.Generators)
+			if err != nil {
+				return nil, fmt.Errorf("topic %s: %v", t.Name, err)
+			}
+		}
+	}
+
+	if err := requireAPIKeys(config, topics, competitions); err != nil {
+		return nil, err
 	}
 
 	// Use OAuth 1.0a (revert from Bearer Token approach)
@@ -139,11 +378,78 @@ func NewNewsBot(config *Config) (*NewsBot, error) {
 	token := oauth1.NewToken(config.XAccessToken, config.XAccessTokenSecret)
 	httpClient := oauthConfig.Client(oauth1.NoContext, token)
 
-	return &NewsBot{
+	var publishers []Publisher
+	if !config.DryRun {
+		publishers, err = buildPublishers(config, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure publishers: %v", err)
+		}
+	}
+
+	colours, err := loadTeamColours(config.TeamColoursFile)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := OpenStore(config.StateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %v", err)
+	}
+
+	nb := &NewsBot{
 		config:       config,
 		geminiClient: geminiClient,
+		generator:    generator,
+		publishers:   publishers,
+		store:        store,
+		topics:       topics,
+		competitions: competitions,
+		colours:      colours,
 		httpClient:   httpClient,
-	}, nil
+		footballRate: newRateLimiter(config.FootballDataRate),
+	}
+	nb.sources = map[string]FootballDataSource{
+		"football-data": &footballDataSource{nb: nb},
+		"thesportsdb":   newTheSportsDB(config.TheSportsDBAPIKey),
+	}
+	if config.FootballDataAPIKey != "" {
+		checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		if err := nb.checkCompetitions(checkCtx); err != nil {
+			return nil, err
+		}
+	}
+	return nb, nil
+}
+
+// requireAPIKeys checks that the API keys of the data providers the topics
+// and live mode use are set; a provider nothing uses needs no key.
+func requireAPIKeys(config *Config, topics []*Topic, competitions map[string]*Competition) error {
+	needsFootballData := len(config.LiveCompetitions) > 0
+	needsNewsAPI := false
+	for _, t := range topics {
+		switch t.Kind {
+		case "news", "crypto":
+			// Feeds are read directly.
+			if t.Query != "" || len(t.Domains) > 0 {
+				needsNewsAPI = true
+			}
+		case "team", "roundup", "scorers", "milestones", "scorers-compare":
+			// Only football-data.org serves these.
+			needsFootballData = true
+		default:
+			if c := competitions[t.League]; c != nil && c.Provider == "football-data" {
+				needsFootballData = true
+			}
+		}
+	}
+	if needsFootballData && config.FootballDataAPIKey == "" {
+		return fmt.Errorf("FOOTBALL_DATA_API_KEY is required for the configured football topics")
+	}
+	if needsNewsAPI && config.NewsAPIKey == "" {
+		return fmt.Errorf("NEWS_API_KEY is required for the configured news topics")
+	}
+	return nil
 }
 
 func (nb *NewsBot) testAuth() error {
@@ -174,475 +480,342 @@ func (nb *NewsBot) debugCredentials() {
 	log.Printf("API Key (first 8 chars): %s...", nb.config.XAPIKey[:min(8, len(nb.config.XAPIKey))])
 }
 
-func (nb *NewsBot) postToTwitter(content string) error {
-	url := "https://api.twitter.com/2/tweets"
-	tweetReq := TweetRequest{Text: content}
-
-	jsonData, err := json.Marshal(tweetReq)
-	if err != nil {
-		return fmt.Errorf("failed to marshal tweet request: %v", err)
+// generateTweet runs the prompt through the topic's generator chain (or the
+// default GENERATOR_CHAIN) and returns the generation with its text cleaned
+// up for posting.
+func (nb *NewsBot) generateTweet(ctx context.Context, topic *Topic, prompt string, opts GenerateOptions) (*Generation, error) {
+	generator := nb.generator
+	if topic != nil && topic.generator != nil {
+		generator = topic.generator
 	}
-
-	fmt.Print(string(jsonData))
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+	if topic != nil && topic.Thread {
+		opts.MaxTokens *= nb.config.ThreadMaxPosts
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	// Remove any Bearer Token headers - OAuth1 client handles auth automatically
-
-	log.Printf("Request Headers: %v", req.Header)
-
-	resp, err := nb.httpClient.Do(req)
+	if lang := topic.language(); lang != "" {
+		opts.SystemPrompt += fmt.Sprintf(" Write in %s.", lang)
+	}
+	gen, err := generator.Generate(ctx, prompt, opts)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	log.Printf("Generated with %s (%s), tokens: %d prompt / %d completion",
+		gen.Provider, gen.Model, gen.Usage.PromptTokens, gen.Usage.CompletionTokens)
+	gen.Text = finishTweet(gen.Text, nb.textLimit(topic))
+	return gen, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %v", err)
+// textLimit is the maximum weighted length of a post for the topic: one
+// tweet, or a full thread for topics that allow threading.
+func (nb *NewsBot) textLimit(topic *Topic) int {
+	if topic != nil && topic.Thread {
+		return (maxTweetLength - len(" 9/9")) * nb.config.ThreadMaxPosts
 	}
-	log.Printf("Raw Response: %s", string(body)) // Debug raw response
+	return maxTweetLength
+}
 
-	var tweetResp TweetResponse
-	if err := json.Unmarshal(body, &tweetResp); err != nil {
-		return fmt.Errorf("failed to parse response: %v, raw response: %s", err, string(body))
+// lengthRequirement tells the model how long the post should be.
+func (nb *NewsBot) lengthRequirement(topic *Topic) string {
+	if topic != nil && topic.Thread {
+		return fmt.Sprintf("at least 400 but under %d characters, in complete sentences so it can be split into a thread", nb.textLimit(topic))
 	}
+	return "at least 100 but under 280 characters"
+}
 
-	if resp.StatusCode != http.StatusCreated {
-		if len(tweetResp.Errors) > 0 {
-			return fmt.Errorf("X API error (status %d): %s", resp.StatusCode, tweetResp.Errors[0].Message)
-		}
-		return fmt.Errorf("X API error (status %d): %s", resp.StatusCode, string(body))
+// customPrompt returns the prompt that replaces the built-in instructions for
+// the topic, if any: the topic's own prompt, or for team topics the
+// configured team prompt.
+func (nb *NewsBot) customPrompt(topic *Topic) string {
+	if topic.Prompt == "" && topic.Kind == "team" {
+		return nb.config.LiverpoolNewsPrompt
 	}
+	return topic.Prompt
+}
 
-	log.Printf("Tweet posted successfully with ID: %s, Text: %s", tweetResp.Data.ID, tweetResp.Data.Text)
-	return nil
+func footballSystemPrompt(hashtags string, limit int) string {
+	return fmt.Sprintf("You are an expert football Twitter writer. Write engaging, informative tweets with emojis where appropriate. Always include relevant hashtags like %s. Keep tweets under %d characters.", hashtags, limit)
 }
 
-func (nb *NewsBot) fetchLatestPremierLeagueMatch(ctx context.Context) (*PremierLeagueMatch, error) {
-	url := "https://api.football-data.org/v4/competitions/PL/matches?status=FINISHED&limit=1"
+// footballData GETs a football-data.org v4 API path, e.g.
+// "/competitions/PL/matches?status=FINISHED", and decodes the JSON response
+// into out.
+func (nb *NewsBot) footballData(ctx context.Context, path string, out interface{}) error {
+	url := "https://api.football-data.org/v4" + path
 	client := &http.Client{Timeout: 10 * time.Second}
 	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	request.Header.Set("X-Auth-Token", nb.config.FootballDataAPIKey)
 	request.Header.Set("Content-Type", "application/json")
+	if err := nb.footballRate.Wait(ctx); err != nil {
+		return err
+	}
 	resp, err := client.Do(request)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
+	nb.footballRate.Observe(resp)
 	if resp.StatusCode != 200 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("football-data.org API error: %s", string(body))
+		return fmt.Errorf("football-data.org API error: %s", string(body))
 	}
-	var matches PremierLeagueMatchesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&matches); err != nil {
-		return nil, err
-	}
-	if len(matches.Matches) == 0 {
-		return nil, fmt.Errorf("no matches found")
-	}
-	return &matches.Matches[len(matches.Matches)-1], nil // latest finished match
+	return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (nb *NewsBot) generatePremierLeagueNewsFromAPI(ctx context.Context) (string, error) {
-	match, err := nb.fetchLatestPremierLeagueMatch(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch latest match: %v", err)
-	}
-	// Format match info for Gemini
-	date := match.UtcDate[:10] // YYYY-MM-DD
-	prompt := fmt.Sprintf(`Generate a tweet about the latest Premier League result:\nDate: %s\n%s %d - %d %s\nMake it concise, engaging, under 280 characters, and include hashtags like #PremierLeague #EPL.`,
-		date, match.HomeTeam.Name, match.Score.FullTime.Home, match.Score.FullTime.Away, match.AwayTeam.Name)
-	model := nb.geminiClient.GenerativeModel("gemini-flash-latest")
-	model.SetTemperature(0.7)
-	model.SetMaxOutputTokens(150)
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		return "", fmt.Errorf("failed to generate summary: %v", err)
-	}
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no content generated")
-	}
-	content := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-	content = strings.TrimSpace(content)
-	content = strings.Trim(content, "\"")
-	if len(content) > 280 {
-		content = content[:277] + "..."
-	}
-	return content, nil
-}
-
-func cleanPerplexityTweet(content string) string {
-	re := regexp.MustCompile(`\s*\(\d+\s*chars\)\s*(\[\d+\])*\s*$`)
-	content = re.ReplaceAllString(content, "")
-	return strings.TrimSpace(content)
-}
-
-func (nb *NewsBot) fetchPerplexityCryptoTweet(ctx context.Context, article *NewsAPIArticle) (string, error) {
-	if nb.config.PerplexityAPIKey == "" {
-		return "", fmt.Errorf("Perplexity API key not set")
-	}
-	url := "https://api.perplexity.ai/chat/completions"
-	payload := map[string]interface{}{
-		"model": "sonar",
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": "You are an expert crypto Twitter writer. Write engaging, informative tweets with emojis where appropriate. Always include relevant hashtags like #Crypto #Blockchain #CryptoNews. Keep tweets under 280 characters.",
-			},
-			{
-				"role":    "user",
-				"content": fmt.Sprintf("Generate a tweet about this crypto news headline and summary.\nThe tweet must be at least 100 characters long, under 280 characters, engaging and informative.\nInclude hashtags like #Crypto #Blockchain #CryptoNews.\n\nTitle: %s\nDescription: %s\nSource: %s", article.Title, article.Description, article.Source.Name),
-			},
-		},
-		"max_tokens":  500,
-		"temperature": 0.8,
-		"top_p":       0.9,
-	}
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %v", err)
-	}
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
-	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("content-type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+nb.config.PerplexityAPIKey)
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to call Perplexity API: %v", err)
+// resultsWindow bounds how far back result queries look, so a topic that
+// hasn't posted for a while doesn't dig up old matches.
+const resultsWindow = 7 * 24 * time.Hour
+
+// matchDuration is how long after kick-off a match may still be finishing.
+const matchDuration = 3 * time.Hour
+
+// resultsFrom is the earliest kick-off of a match that may have finished
+// after since, clamped to resultsWindow before now.
+func resultsFrom(since, now time.Time) time.Time {
+	from := since.Add(-matchDuration)
+	if earliest := now.Add(-resultsWindow); from.Before(earliest) {
+		from = earliest
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("Perplexity API error: %s", string(body))
-	}
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode Perplexity response: %v", err)
-	}
-	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no choices returned from Perplexity")
-	}
-	content := strings.TrimSpace(result.Choices[0].Message.Content)
-	if len(content) > 280 {
-		content = content[:277] + "..."
-	}
-	content = cleanPerplexityTweet(content)
-	return content, nil
-}
-
-func (nb *NewsBot) fetchPerplexityFootballTweet(ctx context.Context, leagueName string, match *PremierLeagueMatch) (string, error) {
-	if nb.config.PerplexityAPIKey == "" {
-		return "", fmt.Errorf("Perplexity API key not set")
-	}
-	url := "https://api.perplexity.ai/chat/completions"
-	prompt := fmt.Sprintf("You are an expert football Twitter writer. Write engaging, informative tweets with emojis where appropriate. Always include relevant hashtags like #%s #Football #FootballNews. Keep tweets under 280 characters. Generate a tweet about the latest %s football result. The tweet must be at least 100 characters long, under 280 characters, engaging and informative.\nMatch: %s %d - %d %s\nDate: %s", leagueName, leagueName, match.HomeTeam.Name, match.Score.FullTime.Home, match.Score.FullTime.Away, match.AwayTeam.Name, match.UtcDate[:10])
-	payload := map[string]interface{}{
-		"model": "sonar",
-		"messages": []map[string]string{
-			{
-				"role":    "system",
-				"content": fmt.Sprintf("You are an expert football Twitter writer. Write engaging, informative tweets with emojis where appropriate. Always include relevant hashtags like #%s #Football #FootballNews. Keep tweets under 280 characters.", leagueName),
-			},
-			{
-				"role":    "user",
-				"content": prompt,
-			},
-		},
-		"max_tokens":  500,
-		"temperature": 0.8,
-		"top_p":       0.9,
-	}
-	jsonData, err := json.Marshal(payload)
+	return from
+}
+
+// fetchResultsSince returns the league's finished matches that haven't been
+// posted and finished after since, oldest first. Results older than
+// resultsWindow are never returned.
+func (nb *NewsBot) fetchResultsSince(ctx context.Context, league string, since time.Time) ([]PremierLeagueMatch, error) {
+	now := time.Now().UTC()
+	from := resultsFrom(since, now)
+	matches, err := fromSources(nb, ctx, league, func(s FootballDataSource, c *Competition) ([]PremierLeagueMatch, error) {
+		return s.Results(ctx, c, from, now)
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal payload: %v", err)
+		return nil, err
 	}
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
+	var fresh []PremierLeagueMatch
+	for _, m := range matches {
+		if m.Status != "FINISHED" || m.UtcDate.Before(from) || nb.store.HasPosted(matchKey(&m)) {
+			continue
+		}
+		fresh = append(fresh, m)
 	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("content-type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+nb.config.PerplexityAPIKey)
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
+	sortByKickoff(fresh)
+	return fresh, nil
+}
+
+// fetchLatestLeagueMatch returns the most recent finished match in the
+// topic's league that hasn't been posted yet and finished since the topic
+// last posted.
+func (nb *NewsBot) fetchLatestLeagueMatch(ctx context.Context, topic *Topic) (*PremierLeagueMatch, error) {
+	since, _ := nb.store.LastPosted(topic.Name)
+	matches, err := nb.fetchResultsSince(ctx, topic.League, since)
 	if err != nil {
-		return "", fmt.Errorf("failed to call Perplexity API: %v", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("Perplexity API error: %s", string(body))
-	}
-	var result struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode Perplexity response: %v", err)
-	}
-	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("no choices returned from Perplexity")
+		return nil, err
 	}
-	content := strings.TrimSpace(result.Choices[0].Message.Content)
-	if len(content) > 280 {
-		content = content[:277] + "..."
+	if len(matches) == 0 {
+		return nil, errNothingNew
 	}
-	content = cleanPerplexityTweet(content)
-	return content, nil
+	return &matches[len(matches)-1], nil
 }
 
-func (nb *NewsBot) generateCryptoNewsFromAPI(ctx context.Context) (string, error) {
-	article, err := nb.fetchLatestCryptoNews(ctx)
+func (nb *NewsBot) generateLeagueNewsFromAPI(ctx context.Context, topic *Topic) (*Draft, error) {
+	match, err := nb.fetchLatestLeagueMatch(ctx, topic)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch crypto news: %v", err)
-	}
-	prompt := fmt.Sprintf(`Generate a tweet about this crypto news headline and summary.\nTitle: %s\nDescription: %s\nSource: %s\nRequirements:\n- The tweet must be at least 100 characters long.\n- Keep it under 280 characters.\n- Make it engaging and informative.\n- Include hashtags like #Crypto #Blockchain #News.`,
-		article.Title, article.Description, article.Source.Name)
-	model := nb.geminiClient.GenerativeModel("gemini-flash-latest")
-	model.SetTemperature(0.7)
-	model.SetMaxOutputTokens(200)
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		log.Println("Gemini API failed, using Perplexity fallback for crypto...")
-		return nb.fetchPerplexityCryptoTweet(ctx, article)
-	}
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		log.Println("Gemini API returned no content, using Perplexity fallback for crypto...")
-		return nb.fetchPerplexityCryptoTweet(ctx, article)
+		return nil, fmt.Errorf("failed to fetch latest match: %w", err)
 	}
-	content := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-	content = strings.TrimSpace(content)
-	content = strings.Trim(content, "\"")
-	if len(content) > 280 {
-		content = content[:277] + "..."
-	}
-	return content, nil
+	return nb.writeMatchResult(ctx, topic, match, topic.DisplayName)
 }
 
-func (nb *NewsBot) fetchLatestCryptoNews(ctx context.Context) (*NewsAPIArticle, error) {
-	url := "https://newsapi.org/v2/top-headlines?q=crypto&pageSize=1"
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
+// writeMatchResult writes a post about a finished match; subject names the
+// league or club the post is about.
+func (nb *NewsBot) writeMatchResult(ctx context.Context, topic *Topic, match *PremierLeagueMatch, subject string) (*Draft, error) {
+	if match.Provider == "" {
+		if detail, err := nb.fetchMatchDetail(ctx, match.ID); err != nil {
+			log.Printf("Failed to fetch details of match %d, writing from the score only: %v", match.ID, err)
+		} else {
+			match = detail
+		}
 	}
-	request.Header.Set("X-Api-Key", nb.config.NewsAPIKey)
-	resp, err := client.Do(request)
+	facts := matchFacts(match)
+	if history := nb.matchContext(ctx, match); history != "" {
+		facts += "\n" + history
+	}
+	var prompt, retryPrompt string
+	if custom := nb.customPrompt(topic); custom != "" {
+		prompt = custom + "\n\n" + facts
+		retryPrompt = prompt + "\n\nThe tweet must be at least 100 characters long."
+	} else {
+		prompt = fmt.Sprintf(`Write a complete, engaging tweet (%s) about the latest %s football result.\n\n%s\n\nMake the tweet informative and detailed, citing the scorers and key moments from the fact sheet where it has them, and using the form and head-to-head record for context. Don't mention any player or event that isn't in the fact sheet. Include hashtags like %s. Output only the tweet text.`,
+			nb.lengthRequirement(topic), subject, facts, topic.hashtags())
+		retryPrompt = fmt.Sprintf(`Write a complete, detailed tweet (%s) about the latest %s football result.\n\n%s\n\nBe detailed and informative. Mention key facts from the fact sheet, such as scorers, and the result's impact. Don't mention any player or event that isn't in the fact sheet. Include hashtags like %s. Output only the tweet text.`,
+			nb.lengthRequirement(topic), subject, facts, topic.hashtags())
+	}
+	opts := GenerateOptions{
+		SystemPrompt: footballSystemPrompt(topic.hashtags(), nb.textLimit(topic)),
+		Temperature:  0.8,
+		MaxTokens:    200,
+	}
+	verify := func(text string) error { return verifyMatchText(text, match) }
+	gen, err := nb.generateVerified(ctx, topic, prompt, opts, verify)
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("newsapi.org API error: %s", string(body))
+		return nil, fmt.Errorf("failed to generate %s tweet: %v", subject, err)
 	}
-	var newsResp NewsAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&newsResp); err != nil {
-		return nil, err
-	}
-	if len(newsResp.Articles) == 0 {
-		return nil, fmt.Errorf("no crypto news found")
+	if tweetWeightedLength(gen.Text) < 100 {
+		// Retry with a stronger prompt if too short
+		retried, err := nb.generateVerified(ctx, topic, retryPrompt, opts, verify)
+		if err != nil {
+			log.Printf("Retry for a longer %s tweet failed, keeping first draft: %v", subject, err)
+		} else {
+			gen = retried
+		}
 	}
-	return &newsResp.Articles[0], nil
+	return &Draft{
+		Topic:     topic.Name,
+		Text:      gen.Text,
+		SourceKey: matchKey(match),
+		Provider:  gen.Provider,
+		Model:     gen.Model,
+		Source:    match,
+		Media:     nb.scoreCardMedia(match, topic),
+	}, nil
 }
 
-func (nb *NewsBot) generatePremierLeagueNews(ctx context.Context) (string, error) {
-	model := nb.geminiClient.GenerativeModel("gemini-flash-latest")
-	model.SetTemperature(0.7)
-	model.SetMaxOutputTokens(150)
-
-	// Get current date for context
-	now := time.Now()
-	currentMonth := now.Format("January")
-	currentDay := now.Day()
-	currentYear := now.Year()
-
-	prompt := fmt.Sprintf(`Generate a concise, engaging tweet with the latest Premier League news as of %s %d, %d.
-
-Requirements:
-- Focus on recent matches, transfers, injuries, standings, or major headlines
-- Mention specific teams, players, or results if possible
-- Keep it under 280 characters
-- Make it interesting for football fans
-- Include relevant hashtags like #PremierLeague #EPL #Football
-- Generate only the tweet text, no quotes or formatting
+// generateTopic fetches source data for the topic and writes a draft post.
+func (nb *NewsBot) generateTopic(ctx context.Context, topic *Topic) (*Draft, error) {
+	switch topic.Kind {
+	case "league":
+		return nb.generateLeagueNewsFromAPI(ctx, topic)
+	case "news", "crypto":
+		return nb.generateNews(ctx, topic)
+	case "preview":
+		return nb.generateMatchPreview(ctx, topic)
+	case "standings":
+		return nb.generateStandings(ctx, topic)
+	case "scorers":
+		return nb.generateScorersRace(ctx, topic)
+	case "milestones":
+		return nb.generateScorerMilestone(ctx, topic)
+	case "scorers-compare":
+		return nb.generateScorersComparison(ctx, topic)
+	case "team":
+		return nb.generateTeamNews(ctx, topic)
+	case "roundup":
+		return nb.generateRoundup(ctx, topic)
+	default:
+		return nil, fmt.Errorf("unknown topic kind %q", topic.Kind)
+	}
+}
 
-Current date context: %s %d, %d`,
-		currentMonth, currentDay, currentYear, currentMonth, currentDay, currentYear)
+// Run performs a single posting run across all topics.
+func (nb *NewsBot) Run(ctx context.Context) error {
+	return nb.runRotation(ctx, nb.topics)
+}
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		return "", fmt.Errorf("failed to generate Premier League news: %v", err)
+// runRotation tries the eligible topics in weighted random order until one
+// produces a post.
+func (nb *NewsBot) runRotation(ctx context.Context, candidates []*Topic) error {
+	topics := nb.eligibleTopics(time.Now(), candidates)
+	if len(topics) == 0 {
+		log.Println("No topics are eligible right now, skipping")
+		return nil
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no content generated")
+	var failures []string
+	for _, topic := range topics {
+		err := nb.runTopic(ctx, topic)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, errNothingNew) {
+			log.Printf("Nothing new for %s, trying next topic", topic.DisplayName)
+			continue
+		}
+		log.Printf("Failed to post %s content: %v", topic.DisplayName, err)
+		failures = append(failures, fmt.Sprintf("%s: %v", topic.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
 	}
-
-	content := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-	content = strings.TrimSpace(content)
-	content = strings.Trim(content, "\"")
-
-	if len(content) > 280 {
-		content = content[:277] + "..."
+	if len(failures) > 0 {
+		return fmt.Errorf("no topic could be posted: %s", strings.Join(failures, "; "))
 	}
-
-	return content, nil
+	log.Println("Nothing new to post since the last run, skipping")
+	return nil
 }
 
-type FootballLeague string
+// runTopic generates, publishes and records a post for a single topic.
+func (nb *NewsBot) runTopic(ctx context.Context, topic *Topic) error {
+	log.Printf("Generating %s content...", topic.DisplayName)
+	draft, err := nb.generateTopic(ctx, topic)
+	if err != nil {
+		return err
+	}
 
-const (
-	PremierLeague FootballLeague = "PL"
-	LaLiga        FootballLeague = "PD"
-	Bundesliga    FootballLeague = "BL1"
-	SerieA        FootballLeague = "SA"
-	Ligue1        FootballLeague = "FL1"
-	IrishPremier  FootballLeague = "IRL"
-)
+	log.Printf("Generated content: %s", draft.Text)
 
-func (nb *NewsBot) fetchLatestLeagueMatch(ctx context.Context, league FootballLeague) (*PremierLeagueMatch, error) {
-	url := fmt.Sprintf("https://api.football-data.org/v4/competitions/%s/matches?status=FINISHED&limit=5", league)
-	client := &http.Client{Timeout: 10 * time.Second}
-	request, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
+	if err := nb.validateDraft(draft, topic); err != nil {
+		return fmt.Errorf("generated %s content is invalid: %v", topic.DisplayName, err)
 	}
-	request.Header.Set("X-Auth-Token", nb.config.FootballDataAPIKey)
-	request.Header.Set("Content-Type", "application/json")
-	resp, err := client.Do(request)
+
+	results, err := nb.publish(ctx, draft.post(topic))
 	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("football-data.org API error: %s", string(body))
-	}
-	var matches PremierLeagueMatchesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&matches); err != nil {
-		return nil, err
+		return fmt.Errorf("failed to publish: %v", err)
 	}
-	if len(matches.Matches) == 0 {
-		return nil, fmt.Errorf("no matches found")
+
+	if err := nb.recordPost(draft, results); err != nil {
+		log.Printf("Warning: failed to record post history: %v", err)
 	}
-	return &matches.Matches[len(matches.Matches)-1], nil // latest finished match
+
+	log.Println("Successfully published content!")
+	return nil
 }
 
-func (nb *NewsBot) generateLeagueNewsFromAPI(ctx context.Context, league FootballLeague, leagueName string) (string, error) {
-	match, err := nb.fetchLatestLeagueMatch(ctx, league)
-	if err != nil {
-		return "", fmt.Errorf("failed to fetch latest match: %v", err)
-	}
-	date := match.UtcDate[:10] // YYYY-MM-DD
-	prompt := fmt.Sprintf(`Write a complete, engaging tweet (at least 100 but under 280 characters) about the latest %s football result.\n\nMatch: %s %d - %d %s\nDate: %s\n\nMake the tweet informative and detailed, mentioning key moments or context if possible. Avoid generic statements. Include hashtags like #%s #Football. Output only the tweet text.`,
-		leagueName, match.HomeTeam.Name, match.Score.FullTime.Home, match.Score.FullTime.Away, match.AwayTeam.Name, date, leagueName)
-	model := nb.geminiClient.GenerativeModel("gemini-flash-latest")
-	model.SetTemperature(0.8)
-	model.SetMaxOutputTokens(200)
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		log.Println("Gemini API failed, using Perplexity fallback for football...")
-		return nb.fetchPerplexityFootballTweet(ctx, leagueName, match)
-	}
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		log.Println("Gemini API returned no content, using Perplexity fallback for football...")
-		return nb.fetchPerplexityFootballTweet(ctx, leagueName, match)
-	}
-	content := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-	content = strings.TrimSpace(content)
-	content = strings.Trim(content, "\"")
-	if len(content) > 280 {
-		content = content[:277] + "..."
+// validateDraft checks the draft fits the topic's length limit, or for an
+// explicit thread that every post fits in a tweet.
+func (nb *NewsBot) validateDraft(draft *Draft, topic *Topic) error {
+	if len(draft.Segments) == 0 {
+		return validateTweet(draft.Text, nb.textLimit(topic))
 	}
-	if len(content) < 100 {
-		// Retry with a stronger prompt if too short
-		retryPrompt := fmt.Sprintf(`Write a complete, detailed tweet (at least 100 but under 280 characters) about the latest %s football result.\n\nMatch: %s %d - %d %s\nDate: %s\n\nBe detailed and informative. Mention key facts, context, and impact. Avoid generic statements. Include hashtags like #%s #Football. Output only the tweet text.`,
-			leagueName, match.HomeTeam.Name, match.Score.FullTime.Home, match.Score.FullTime.Away, match.AwayTeam.Name, date, leagueName)
-		resp, err = model.GenerateContent(ctx, genai.Text(retryPrompt))
-		if err != nil {
-			log.Println("Gemini API failed on retry, using Perplexity fallback for football...")
-			return nb.fetchPerplexityFootballTweet(ctx, leagueName, match)
-		}
-		if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-			log.Println("Gemini API returned no content on retry, using Perplexity fallback for football...")
-			return nb.fetchPerplexityFootballTweet(ctx, leagueName, match)
-		}
-		content = fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-		content = strings.TrimSpace(content)
-		content = strings.Trim(content, "\"")
-		if len(content) > 280 {
-			content = content[:277] + "..."
+	for i, segment := range draft.Segments {
+		if err := validateTweet(segment, maxTweetLength); err != nil {
+			return fmt.Errorf("post %d of %d: %v", i+1, len(draft.Segments), err)
 		}
 	}
-	return content, nil
+	return nil
 }
 
-func (nb *NewsBot) Run() error {
-	ctx := context.Background()
+// post builds the Post to publish for the draft.
+func (d *Draft) post(topic *Topic) *Post {
+	return &Post{Topic: d.Topic, Text: d.Text, Thread: topic.Thread, Segments: d.Segments, Media: d.Media}
+}
 
-	var content string
-	var err error
-
-	// Randomly select news type: 0 = Premier League, 1 = La Liga, 2 = Bundesliga, 3 = Serie A, 4 = Ligue 1, 5 = Irish Premier, 6 = Crypto
-	switch rand.Intn(7) {
-	case 0:
-		log.Println("Generating Premier League news content from API...")
-		content, err = nb.generateLeagueNewsFromAPI(ctx, PremierLeague, "PremierLeague")
-	case 1:
-		log.Println("Generating La Liga news content from API...")
-		content, err = nb.generateLeagueNewsFromAPI(ctx, LaLiga, "LaLiga")
-	case 2:
-		log.Println("Generating Bundesliga news content from API...")
-		content, err = nb.generateLeagueNewsFromAPI(ctx, Bundesliga, "Bundesliga")
-	case 3:
-		log.Println("Generating Serie A news content from API...")
-		content, err = nb.generateLeagueNewsFromAPI(ctx, SerieA, "SerieA")
-	case 4:
-		log.Println("Generating Ligue 1 news content from API...")
-		content, err = nb.generateLeagueNewsFromAPI(ctx, Ligue1, "Ligue1")
-	case 5:
-		log.Println("Generating Irish Premier Division news content from API...")
-		content, err = nb.generateLeagueNewsFromAPI(ctx, IrishPremier, "IrishPremierDivision")
-	case 6:
-		log.Println("Generating Crypto news content from API...")
-		content, err = nb.generateCryptoNewsFromAPI(ctx)
+// recordPost stores a published draft with the IDs returned by each target.
+func (nb *NewsBot) recordPost(draft *Draft, results []PublishResult) error {
+	ids := make(map[string]string)
+	var errs map[string]string
+	for _, r := range results {
+		if r.ID != "" {
+			ids[r.Publisher] = r.ID
+		}
+		if r.Err != nil {
+			if errs == nil {
+				errs = make(map[string]string)
+			}
+			errs[r.Publisher] = r.Err.Error()
+		}
 	}
-
-	if err != nil {
-		return fmt.Errorf("failed to generate news: %v", err)
+	if err := nb.store.Record(PostRecord{
+		Key:     draft.SourceKey,
+		Topic:   draft.Topic,
+		Text:    draft.Text,
+		PostIDs: ids,
+		Errors:  errs,
+	}); err != nil {
+		return err
 	}
-
-	log.Printf("Generated content: %s", content)
-
-	log.Println("Posting to X...")
-	err = nb.postToTwitter(content)
-	if err != nil {
-		return fmt.Errorf("failed to post to X: %v", err)
+	if draft.onRecorded != nil {
+		return draft.onRecorded()
 	}
-
-	log.Println("Successfully posted content to X!")
 	return nil
 }
 
@@ -658,23 +831,23 @@ func main() {
 	// Seed the random number generator once at startup
 	rand.Seed(time.Now().UnixNano())
 
-	config, err := loadConfig()
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
-	}
-
-	bot, err := NewNewsBot(config)
-	if err != nil {
-		log.Fatalf("Failed to create news bot: %v", err)
+	command, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	switch command {
+	case "run":
+		runCommand(args)
+	case "serve":
+		serveCommand(args)
+	case "live":
+		liveCommand(args)
+	case "generate":
+		os.Exit(generateCommand(args))
+	case "help":
+		printUsage()
+	default:
+		printUsage()
+		log.Fatalf("Unknown command %q", command)
 	}
-	defer bot.Close()
-
-	// Add credential debugging
-	bot.debugCredentials()
-
-	if err := bot.Run(); err != nil {
-		log.Fatalf("Bot execution failed: %v", err)
-	}
-
-	log.Println("Bot execution completed successfully!")
 }
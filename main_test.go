@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequireAPIKeys(t *testing.T) {
+	competitions := map[string]*Competition{
+		"PL":  {Code: "PL", Provider: "football-data", Fallback: "thesportsdb"},
+		"IRL": {Code: "IRL", Provider: "thesportsdb"},
+	}
+	tests := []struct {
+		name    string
+		config  Config
+		topics  []*Topic
+		wantErr string
+	}{
+		{"feeds only", Config{}, []*Topic{{Kind: "news", Feeds: []string{"https://example.com/rss"}}}, ""},
+		{"thesportsdb league", Config{}, []*Topic{{Kind: "league", League: "IRL"}}, ""},
+		{"football-data league", Config{}, []*Topic{{Kind: "league", League: "PL"}}, "FOOTBALL_DATA_API_KEY"},
+		{"team", Config{}, []*Topic{{Kind: "team", Teams: []int{64}}}, "FOOTBALL_DATA_API_KEY"},
+		{"live", Config{LiveCompetitions: []string{"PL"}}, nil, "FOOTBALL_DATA_API_KEY"},
+		{"news query", Config{FootballDataAPIKey: "fd"}, []*Topic{{Kind: "crypto", Query: "crypto"}}, "NEWS_API_KEY"},
+		{"both set", Config{FootballDataAPIKey: "fd", NewsAPIKey: "news"},
+			[]*Topic{{Kind: "league", League: "PL"}, {Kind: "news", Query: "Formula 1"}}, ""},
+	}
+	for _, tt := range tests {
+		err := requireAPIKeys(&tt.config, tt.topics, competitions)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: error = %v, want one mentioning %s", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MatchPlayer is a player as referenced in match events and lineups.
+type MatchPlayer struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Position    string `json:"position,omitempty"`
+	ShirtNumber int    `json:"shirtNumber,omitempty"`
+}
+
+// Referee is one of a match's officials.
+type Referee struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"` // REFEREE, ASSISTANT_REFEREE_N1, VIDEO_ASSISTANT_REFEREE_N1, ...
+	Nationality string `json:"nationality,omitempty"`
+}
+
+// MatchGoal is a goal in the match detail.
+type MatchGoal struct {
+	Minute     int          `json:"minute"`
+	InjuryTime int          `json:"injuryTime,omitempty"`
+	Type       string       `json:"type"` // REGULAR, OWN or PENALTY
+	Team       MatchTeam    `json:"team"`
+	Scorer     MatchPlayer  `json:"scorer"`
+	Assist     *MatchPlayer `json:"assist,omitempty"`
+	Score      struct {
+		Home int `json:"home"`
+		Away int `json:"away"`
+	} `json:"score"` // the score after the goal
+}
+
+// MatchBooking is a card shown in the match detail.
+type MatchBooking struct {
+	Minute int         `json:"minute"`
+	Team   MatchTeam   `json:"team"`
+	Player MatchPlayer `json:"player"`
+	Card   string      `json:"card"` // YELLOW, YELLOW_RED or RED
+}
+
+// MatchSubstitution is a substitution in the match detail.
+type MatchSubstitution struct {
+	Minute    int         `json:"minute"`
+	Team      MatchTeam   `json:"team"`
+	PlayerOut MatchPlayer `json:"playerOut"`
+	PlayerIn  MatchPlayer `json:"playerIn"`
+}
+
+// fetchMatchDetail returns the full football-data.org record of a match,
+// with referees and, where the plan includes them, goals, bookings,
+// substitutions and lineups.
+func (nb *NewsBot) fetchMatchDetail(ctx context.Context, id int) (*PremierLeagueMatch, error) {
+	var match PremierLeagueMatch
+	if err := nb.footballData(ctx, fmt.Sprintf("/matches/%d", id), &match); err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+// minuteLabel formats a match minute, e.g. "23'" or "90+4'".
+func minuteLabel(minute, injuryTime int) string {
+	if injuryTime > 0 {
+		return fmt.Sprintf("%d+%d'", minute, injuryTime)
+	}
+	return fmt.Sprintf("%d'", minute)
+}
+
+// matchFacts is the fact sheet a result post is written from: the score and
+// everything known about how it came about.
+func matchFacts(m *PremierLeagueMatch) string {
+	var b strings.Builder
+	if m.Competition.Name != "" {
+		b.WriteString("Competition: " + m.Competition.Name)
+		if m.Matchday > 0 {
+			fmt.Fprintf(&b, ", matchday %d", m.Matchday)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "Match: %s %d - %d %s", m.HomeTeam.Name, m.Score.FullTime.Home, m.Score.FullTime.Away, m.AwayTeam.Name)
+	switch m.Score.Duration {
+	case "EXTRA_TIME":
+		b.WriteString(" after extra time")
+	case "PENALTY_SHOOTOUT":
+		fmt.Fprintf(&b, ", %d - %d on penalties", m.Score.Penalties.Home, m.Score.Penalties.Away)
+	}
+	b.WriteString("\n")
+	switch m.Score.Winner {
+	case "HOME_TEAM":
+		fmt.Fprintf(&b, "Winner: %s\n", m.HomeTeam.Name)
+	case "AWAY_TEAM":
+		fmt.Fprintf(&b, "Winner: %s\n", m.AwayTeam.Name)
+	case "DRAW":
+		b.WriteString("Result: draw\n")
+	}
+	// Only football-data.org reports the half-time score.
+	if m.Provider == "" {
+		fmt.Fprintf(&b, "Half-time: %d - %d\n", m.Score.HalfTime.Home, m.Score.HalfTime.Away)
+	}
+	fmt.Fprintf(&b, "Date: %s\n", m.UtcDate.Format("2006-01-02"))
+	if m.Venue != "" {
+		fmt.Fprintf(&b, "Venue: %s\n", m.Venue)
+	}
+	for _, r := range m.Referees {
+		if r.Type == "REFEREE" {
+			fmt.Fprintf(&b, "Referee: %s\n", r.Name)
+		}
+	}
+
+	if len(m.Goals) > 0 {
+		b.WriteString("Goals:\n")
+		for _, g := range m.Goals {
+			fmt.Fprintf(&b, "- %s %s (%s)", minuteLabel(g.Minute, g.InjuryTime), g.Scorer.Name, g.Team.Name)
+			switch g.Type {
+			case "PENALTY":
+				b.WriteString(", penalty")
+			case "OWN":
+				b.WriteString(", own goal")
+			}
+			if g.Assist != nil && g.Assist.Name != "" {
+				fmt.Fprintf(&b, ", assisted by %s", g.Assist.Name)
+			}
+			fmt.Fprintf(&b, ", making it %d - %d\n", g.Score.Home, g.Score.Away)
+		}
+	}
+	var cards []string
+	for _, c := range m.Bookings {
+		card := "yellow card"
+		switch c.Card {
+		case "RED":
+			card = "red card"
+		case "YELLOW_RED":
+			card = "second yellow, sent off"
+		}
+		cards = append(cards, fmt.Sprintf("- %s %s (%s), %s", minuteLabel(c.Minute, 0), c.Player.Name, c.Team.Name, card))
+	}
+	if len(cards) > 0 {
+		b.WriteString("Cards:\n" + strings.Join(cards, "\n") + "\n")
+	}
+	if len(m.Substitutions) > 0 {
+		b.WriteString("Substitutions:\n")
+		for _, s := range m.Substitutions {
+			fmt.Fprintf(&b, "- %s %s on for %s (%s)\n", minuteLabel(s.Minute, 0), s.PlayerIn.Name, s.PlayerOut.Name, s.Team.Name)
+		}
+	}
+	for _, team := range []MatchTeam{m.HomeTeam, m.AwayTeam} {
+		if len(team.Lineup) == 0 {
+			continue
+		}
+		names := make([]string, len(team.Lineup))
+		for i, p := range team.Lineup {
+			names[i] = p.Name
+		}
+		b.WriteString(team.Name + " lineup")
+		if team.Formation != "" {
+			b.WriteString(" (" + team.Formation + ")")
+		}
+		b.WriteString(": " + strings.Join(names, ", ") + "\n")
+	}
+	return strings.TrimSpace(b.String())
+}
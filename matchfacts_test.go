@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMinuteLabel(t *testing.T) {
+	if got := minuteLabel(23, 0); got != "23'" {
+		t.Errorf("minuteLabel(23, 0) = %q", got)
+	}
+	if got := minuteLabel(90, 4); got != "90+4'" {
+		t.Errorf("minuteLabel(90, 4) = %q", got)
+	}
+}
+
+func TestMatchFacts(t *testing.T) {
+	m := &PremierLeagueMatch{
+		HomeTeam: MatchTeam{Name: "Wolverhampton Wanderers FC", Formation: "3-4-3",
+			Lineup: []MatchPlayer{{Name: "José Sá"}, {Name: "Nélson Semedo"}}},
+		AwayTeam: MatchTeam{Name: "Liverpool FC"},
+		UtcDate:  time.Date(2025, time.March, 1, 15, 0, 0, 0, time.UTC),
+		Venue:    "Molineux Stadium",
+		Matchday: 27,
+		Referees: []Referee{{Name: "Anthony Taylor", Type: "REFEREE"}, {Name: "Gary Beswick", Type: "ASSISTANT_REFEREE_N1"}},
+	}
+	m.Competition.Name = "Premier League"
+	m.Score.Winner = "AWAY_TEAM"
+	m.Score.FullTime.Home, m.Score.FullTime.Away = 1, 2
+	m.Score.HalfTime.Home, m.Score.HalfTime.Away = 0, 1
+
+	var penalty, equaliser MatchGoal
+	penalty.Minute, penalty.Type = 37, "PENALTY"
+	penalty.Team, penalty.Scorer = m.AwayTeam, MatchPlayer{Name: "Mohamed Salah"}
+	penalty.Score.Away = 1
+	equaliser.Minute, equaliser.InjuryTime, equaliser.Type = 45, 2, "REGULAR"
+	equaliser.Team, equaliser.Scorer = m.HomeTeam, MatchPlayer{Name: "Matheus Cunha"}
+	equaliser.Assist = &MatchPlayer{Name: "Rayan Aït-Nouri"}
+	equaliser.Score.Home, equaliser.Score.Away = 1, 1
+	m.Goals = []MatchGoal{penalty, equaliser}
+	m.Bookings = []MatchBooking{{Minute: 80, Team: m.HomeTeam, Player: MatchPlayer{Name: "Nélson Semedo"}, Card: "YELLOW_RED"}}
+	m.Substitutions = []MatchSubstitution{{Minute: 60, Team: m.AwayTeam,
+		PlayerOut: MatchPlayer{Name: "Luis Díaz"}, PlayerIn: MatchPlayer{Name: "Diogo Jota"}}}
+
+	facts := matchFacts(m)
+	for _, want := range []string{
+		"Competition: Premier League, matchday 27\n",
+		"Match: Wolverhampton Wanderers FC 1 - 2 Liverpool FC\n",
+		"Winner: Liverpool FC\n",
+		"Half-time: 0 - 1\n",
+		"Date: 2025-03-01\n",
+		"Venue: Molineux Stadium\n",
+		"Referee: Anthony Taylor\n",
+		"- 37' Mohamed Salah (Liverpool FC), penalty, making it 0 - 1\n",
+		"- 45+2' Matheus Cunha (Wolverhampton Wanderers FC), assisted by Rayan Aït-Nouri, making it 1 - 1\n",
+		"- 80' Nélson Semedo (Wolverhampton Wanderers FC), second yellow, sent off\n",
+		"- 60' Diogo Jota on for Luis Díaz (Liverpool FC)\n",
+		"Wolverhampton Wanderers FC lineup (3-4-3): José Sá, Nélson Semedo",
+	} {
+		if !strings.Contains(facts, want) {
+			t.Errorf("facts missing %q:\n%s", want, facts)
+		}
+	}
+	if strings.Contains(facts, "Gary Beswick") {
+		t.Errorf("facts list an assistant referee:\n%s", facts)
+	}
+}
+
+func TestMatchFactsWithoutHalfTime(t *testing.T) {
+	m := &PremierLeagueMatch{
+		HomeTeam: MatchTeam{Name: "Wolverhampton Wanderers"},
+		AwayTeam: MatchTeam{Name: "Liverpool"},
+		Provider: "thesportsdb",
+	}
+	if facts := matchFacts(m); strings.Contains(facts, "Half-time") {
+		t.Errorf("facts give a half-time score the provider doesn't report:\n%s", facts)
+	}
+}
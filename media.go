@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	xMediaUploadURL   = "https://upload.twitter.com/1.1/media/upload.json"
+	xMediaMetadataURL = "https://upload.twitter.com/1.1/media/metadata/create.json"
+	xMediaChunkSize   = 1 << 20 // APPEND segments may be at most 5 MB
+)
+
+// mediaUploadResponse is returned by the INIT, FINALIZE and STATUS commands.
+type mediaUploadResponse struct {
+	MediaIDString  string `json:"media_id_string"`
+	ProcessingInfo *struct {
+		State          string `json:"state"` // pending, in_progress, succeeded or failed
+		CheckAfterSecs int    `json:"check_after_secs"`
+		Error          *struct {
+			Message string `json:"message"`
+		} `json:"error,omitempty"`
+	} `json:"processing_info,omitempty"`
+}
+
+// uploadMedia uploads an image with the chunked INIT/APPEND/FINALIZE flow and
+// returns its media ID for attaching to a tweet.
+func (x *XPublisher) uploadMedia(ctx context.Context, m Media) (string, error) {
+	started, err := x.mediaCommand(ctx, "POST", url.Values{
+		"command":        {"INIT"},
+		"total_bytes":    {strconv.Itoa(len(m.Data))},
+		"media_type":     {m.MimeType},
+		"media_category": {"tweet_image"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("media INIT failed: %v", err)
+	}
+	mediaID := started.MediaIDString
+
+	for i, offset := 0, 0; offset < len(m.Data); i, offset = i+1, offset+xMediaChunkSize {
+		chunk := m.Data[offset:min(offset+xMediaChunkSize, len(m.Data))]
+		if err := x.appendMedia(ctx, mediaID, i, chunk); err != nil {
+			return "", fmt.Errorf("media APPEND segment %d failed: %v", i, err)
+		}
+	}
+
+	status, err := x.mediaCommand(ctx, "POST", url.Values{
+		"command":  {"FINALIZE"},
+		"media_id": {mediaID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("media FINALIZE failed: %v", err)
+	}
+	// Images are usually ready immediately; wait out any async processing.
+	for status.ProcessingInfo != nil && status.ProcessingInfo.State != "succeeded" {
+		info := status.ProcessingInfo
+		if info.State == "failed" {
+			msg := "unknown error"
+			if info.Error != nil {
+				msg = info.Error.Message
+			}
+			return "", fmt.Errorf("media processing failed: %s", msg)
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Duration(max(info.CheckAfterSecs, 1)) * time.Second):
+		}
+		if status, err = x.mediaCommand(ctx, "GET", url.Values{
+			"command":  {"STATUS"},
+			"media_id": {mediaID},
+		}); err != nil {
+			return "", fmt.Errorf("media STATUS failed: %v", err)
+		}
+	}
+
+	if m.AltText != "" {
+		if err := x.setAltText(ctx, mediaID, m.AltText); err != nil {
+			log.Printf("Warning: failed to set alt text on media %s: %v", mediaID, err)
+		}
+	}
+	log.Printf("Uploaded media %s (%d bytes)", mediaID, len(m.Data))
+	return mediaID, nil
+}
+
+// mediaCommand sends a form-encoded INIT, FINALIZE or STATUS command. The
+// parameters are form fields so the OAuth1 client includes them in the
+// signature.
+func (x *XPublisher) mediaCommand(ctx context.Context, method string, params url.Values) (*mediaUploadResponse, error) {
+	var req *http.Request
+	var err error
+	if method == "GET" {
+		req, err = http.NewRequestWithContext(ctx, method, xMediaUploadURL+"?"+params.Encode(), nil)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, xMediaUploadURL, strings.NewReader(params.Encode()))
+		if req != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	body, err := x.doMedia(req)
+	if err != nil {
+		return nil, err
+	}
+	var out mediaUploadResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v, raw response: %s", err, string(body))
+	}
+	if out.MediaIDString == "" {
+		return nil, fmt.Errorf("response has no media ID: %s", string(body))
+	}
+	return &out, nil
+}
+
+// appendMedia uploads one segment as multipart form data.
+func (x *XPublisher) appendMedia(ctx context.Context, mediaID string, index int, chunk []byte) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	w.WriteField("command", "APPEND")
+	w.WriteField("media_id", mediaID)
+	w.WriteField("segment_index", strconv.Itoa(index))
+	part, err := w.CreateFormFile("media", "media")
+	if err != nil {
+		return err
+	}
+	part.Write(chunk)
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", xMediaUploadURL, &buf)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	_, err = x.doMedia(req)
+	return err
+}
+
+func (x *XPublisher) setAltText(ctx context.Context, mediaID, alt string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"media_id": mediaID,
+		"alt_text": map[string]string{"text": truncateTweet(alt, 1000)},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", xMediaMetadataURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = x.doMedia(req)
+	return err
+}
+
+// doMedia sends a media API request and returns the body of a 2xx response.
+func (x *XPublisher) doMedia(req *http.Request) ([]byte, error) {
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("X media API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
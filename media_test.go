@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUploadMedia(t *testing.T) {
+	data := bytes.Repeat([]byte{0xab}, 2*xMediaChunkSize+100)
+	var mu sync.Mutex
+	var commands []string
+	var appended []int // segment sizes by index
+	var alt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/1.1/media/metadata/create.json" {
+			var meta struct {
+				MediaID string            `json:"media_id"`
+				AltText map[string]string `json:"alt_text"`
+			}
+			json.NewDecoder(r.Body).Decode(&meta)
+			alt = meta.MediaID + ": " + meta.AltText["text"]
+			w.Write([]byte(`{}`))
+			return
+		}
+		if r.URL.Path != "/1.1/media/upload.json" {
+			http.NotFound(w, r)
+			return
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			if err := r.ParseMultipartForm(4 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			f, _, err := r.FormFile("media")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			chunk, _ := io.ReadAll(f)
+			if r.FormValue("command") != "APPEND" || r.FormValue("media_id") != "710" ||
+				r.FormValue("segment_index") != strconv.Itoa(len(appended)) {
+				http.Error(w, "bad APPEND "+r.FormValue("segment_index"), http.StatusBadRequest)
+				return
+			}
+			appended = append(appended, len(chunk))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		r.ParseForm()
+		switch cmd := r.FormValue("command"); cmd {
+		case "INIT":
+			commands = append(commands, cmd+" "+r.FormValue("total_bytes")+" "+r.FormValue("media_type"))
+			w.Write([]byte(`{"media_id_string":"710"}`))
+		case "FINALIZE":
+			commands = append(commands, cmd+" "+r.FormValue("media_id"))
+			w.Write([]byte(`{"media_id_string":"710"}`))
+		default:
+			http.Error(w, "unexpected command "+cmd, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	x := NewXPublisher(redirectedClient(srv), false, 0)
+	id, err := x.uploadMedia(context.Background(), Media{Data: data, MimeType: "image/png", AltText: "Wolves 1-3 Liverpool"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "710" {
+		t.Errorf("media ID = %q", id)
+	}
+	wantCommands := []string{"INIT 2097252 image/png", "FINALIZE 710"}
+	if strings.Join(commands, ", ") != strings.Join(wantCommands, ", ") {
+		t.Errorf("commands %q, want %q", commands, wantCommands)
+	}
+	if len(appended) != 3 || appended[0] != xMediaChunkSize || appended[1] != xMediaChunkSize || appended[2] != 100 {
+		t.Errorf("APPEND segment sizes %v", appended)
+	}
+	if alt != "710: Wolves 1-3 Liverpool" {
+		t.Errorf("alt text request %q", alt)
+	}
+}
+
+func TestUploadMediaProcessingFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		r.ParseForm()
+		if r.FormValue("command") == "FINALIZE" {
+			w.Write([]byte(`{"media_id_string":"710","processing_info":{"state":"failed","error":{"message":"unsupported image"}}}`))
+			return
+		}
+		w.Write([]byte(`{"media_id_string":"710"}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewXPublisher(redirectedClient(srv), false, 0).uploadMedia(context.Background(), Media{Data: []byte("png"), MimeType: "image/png"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported image") {
+		t.Errorf("error = %v, want the processing error", err)
+	}
+}
+
+func TestScoreCardRender(t *testing.T) {
+	sc := &ScoreCard{
+		Competition:     "Premier League",
+		CompetitionCode: "PL",
+		Date:            time.Date(2025, time.March, 1, 15, 0, 0, 0, time.UTC),
+		HomeTeam:        "Wolverhampton Wanderers",
+		AwayTeam:        "Liverpool",
+		HomeScore:       1,
+		AwayScore:       3,
+		HomeColours:     TeamColours{Primary: "#fdb913", Secondary: "#231f20"},
+		AwayColours:     TeamColours{Primary: "#c8102e", Secondary: "#ffffff"},
+	}
+	raw, err := sc.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != cardWidth || b.Dy() != cardHeight {
+		t.Errorf("card is %dx%d, want %dx%d", b.Dx(), b.Dy(), cardWidth, cardHeight)
+	}
+	// Panel corners are clear of the team names.
+	for _, tt := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{2, cardHeight - 92, mustHexColor("#fdb913")},
+		{cardWidth - 3, cardHeight - 92, mustHexColor("#c8102e")},
+		{2, 2, cardBackground},
+	} {
+		r, g, b, _ := img.At(tt.x, tt.y).RGBA()
+		if uint8(r>>8) != tt.want.R || uint8(g>>8) != tt.want.G || uint8(b>>8) != tt.want.B {
+			t.Errorf("pixel (%d, %d) = %v, want %v", tt.x, tt.y, img.At(tt.x, tt.y), tt.want)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	if c, err := parseHexColor("#c8102e"); err != nil || c != (color.RGBA{0xc8, 0x10, 0x2e, 0xff}) {
+		t.Errorf("parseHexColor(#c8102e) = %v, %v", c, err)
+	}
+	for _, s := range []string{"", "red", "#12zz56"} {
+		if _, err := parseHexColor(s); err == nil {
+			t.Errorf("parseHexColor(%q) succeeded", s)
+		}
+	}
+}
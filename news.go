@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// newsPageSize is how many articles are fetched to choose from.
+	newsPageSize = 50
+	// headlineWindow is how long a posted headline blocks near-duplicates,
+	// the same story reported by another outlet.
+	headlineWindow = 72 * time.Hour
+	// duplicateSimilarity is how similar two headlines' key words must be to
+	// count as the same story: the words they share over all the words in
+	// either (their Jaccard similarity).
+	duplicateSimilarity = 0.5
+)
+
+// newsAPI GETs a NewsAPI v2 endpoint, e.g. "top-headlines", with the given
+// query parameters.
+func (nb *NewsBot) newsAPI(ctx context.Context, endpoint string, params url.Values) (*NewsAPIResponse, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	request, err := http.NewRequestWithContext(ctx, "GET", "https://newsapi.org/v2/"+endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Api-Key", nb.config.NewsAPIKey)
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("newsapi.org API error: %s", string(body))
+	}
+	var newsResp NewsAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&newsResp); err != nil {
+		return nil, err
+	}
+	return &newsResp, nil
+}
+
+// newsSystemPrompt sets up the model as the topic's persona.
+func newsSystemPrompt(persona, hashtags string, limit int) string {
+	return fmt.Sprintf("You are %s. Write engaging, informative tweets with emojis where appropriate. Always include relevant hashtags like %s. Keep tweets under %d characters.", persona, hashtags, limit)
+}
+
+// generateNews writes a post about the best new article on a news topic's
+// beat, ending with the article link.
+func (nb *NewsBot) generateNews(ctx context.Context, topic *Topic) (*Draft, error) {
+	article, err := nb.fetchNews(ctx, topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s news: %w", topic.DisplayName, err)
+	}
+	// Leave room for the link appended to the post.
+	limit := nb.textLimit(topic) - tweetTextURLLength - 2
+	var prompt string
+	if topic.Prompt != "" {
+		prompt = fmt.Sprintf("%s\n\nTitle: %s\nDescription: %s\nSource: %s",
+			topic.Prompt, article.Title, article.Description, article.Source.Name)
+	} else {
+		prompt = fmt.Sprintf(`Generate a tweet about this %s news headline and summary.\nTitle: %s\nDescription: %s\nSource: %s\nRequirements:\n- The tweet must be %s, and no more than %d characters.\n- Make it engaging and informative.\n- Don't include a link; the article link is added after the tweet.\n- Include hashtags like %s.`,
+			topic.DisplayName, article.Title, article.Description, article.Source.Name, nb.lengthRequirement(topic), limit, topic.hashtags())
+	}
+	gen, err := nb.generateVerified(ctx, topic, prompt, GenerateOptions{
+		SystemPrompt: newsSystemPrompt(topic.Persona, topic.hashtags(), limit),
+		Temperature:  0.7,
+		MaxTokens:    200,
+	}, func(text string) error { return verifyArticleText(text, article) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s tweet: %v", topic.DisplayName, err)
+	}
+	return &Draft{
+		Topic:     topic.Name,
+		Text:      withArticleLink(gen.Text, article, nb.textLimit(topic)),
+		SourceKey: articleKey(article),
+		Provider:  gen.Provider,
+		Model:     gen.Model,
+		Source:    article,
+		onRecorded: func() error {
+			return nb.store.AddHeadline(article.Title)
+		},
+	}, nil
+}
+
+// fetchNews returns the best article on the topic's beat not yet posted. It
+// gathers candidates from NewsAPI and the topic's feeds, drops posted URLs
+// and stories already covered under another headline, and ranks the rest by
+// source and recency.
+func (nb *NewsBot) fetchNews(ctx context.Context, topic *Topic) (*NewsAPIArticle, error) {
+	var candidates []NewsAPIArticle
+	var failures []string
+	if topic.Query != "" || len(topic.Domains) > 0 {
+		articles, err := nb.fetchNewsAPI(ctx, topic)
+		if err != nil {
+			log.Printf("Failed to fetch %s news from newsapi.org: %v", topic.DisplayName, err)
+			failures = append(failures, fmt.Sprintf("newsapi.org: %v", err))
+		}
+		candidates = append(candidates, articles...)
+	}
+	for _, feed := range topic.Feeds {
+		articles, err := nb.fetchFeed(ctx, feed)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			log.Printf("Failed to fetch feed %s: %v", feed, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", feed, err))
+			continue
+		}
+		for _, a := range articles {
+			// Feeds keep old items around; only recent ones are news.
+			if time.Since(a.PublishedAt) <= feedItemMaxAge {
+				candidates = append(candidates, a)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		if len(failures) > 0 {
+			return nil, errors.New(strings.Join(failures, "; "))
+		}
+		return nil, fmt.Errorf("no articles found")
+	}
+
+	articles := nb.rankArticles(candidates)
+	seen := nb.store.RecentHeadlines(headlineWindow)
+	for _, article := range articles {
+		if nb.store.HasPosted(articleKey(article)) {
+			continue
+		}
+		if similarHeadline(article.Title, seen) {
+			continue
+		}
+		return article, nil
+	}
+	return nil, errNothingNew
+}
+
+// fetchNewsAPI returns a page of top headlines for the topic, or of all
+// recent articles when there are no top headlines or the topic is limited to
+// certain domains.
+func (nb *NewsBot) fetchNewsAPI(ctx context.Context, topic *Topic) ([]NewsAPIArticle, error) {
+	if len(topic.Domains) == 0 {
+		params := url.Values{"q": {topic.Query}, "pageSize": {fmt.Sprint(newsPageSize)}}
+		if topic.Country != "" {
+			params.Set("country", topic.Country)
+		}
+		newsResp, err := nb.newsAPI(ctx, "top-headlines", params)
+		if err != nil {
+			return nil, err
+		}
+		if len(newsResp.Articles) > 0 {
+			return newsResp.Articles, nil
+		}
+	}
+	params := url.Values{
+		"sortBy":   {"publishedAt"},
+		"pageSize": {fmt.Sprint(newsPageSize)},
+	}
+	if topic.Query != "" {
+		params.Set("q", topic.Query)
+	}
+	if topic.Language != "" {
+		params.Set("language", topic.Language)
+	}
+	if len(topic.Domains) > 0 {
+		params.Set("domains", strings.Join(topic.Domains, ","))
+	}
+	newsResp, err := nb.newsAPI(ctx, "everything", params)
+	if err != nil {
+		return nil, err
+	}
+	return newsResp.Articles, nil
+}
+
+// rankArticles drops articles without a usable title or URL and orders the
+// rest with NEWS_SOURCES outlets first, in the order listed, then newest
+// first.
+func (nb *NewsBot) rankArticles(articles []NewsAPIArticle) []*NewsAPIArticle {
+	rank := func(a *NewsAPIArticle) int {
+		for i, src := range nb.config.NewsSources {
+			if src == strings.ToLower(a.Source.ID) || src == strings.ToLower(a.Source.Name) {
+				return i
+			}
+		}
+		return len(nb.config.NewsSources)
+	}
+	var out []*NewsAPIArticle
+	for i := range articles {
+		a := &articles[i]
+		// NewsAPI blanks out articles pulled by their publisher.
+		if a.Title == "" || a.Url == "" || a.Title == "[Removed]" {
+			continue
+		}
+		out = append(out, a)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if ri, rj := rank(out[i]), rank(out[j]); ri != rj {
+			return ri < rj
+		}
+		return out[i].PublishedAt.After(out[j].PublishedAt)
+	})
+	// Of several outlets carrying the same story, keep the best ranked.
+	var unique []*NewsAPIArticle
+	var titles []string
+	for _, a := range out {
+		if similarHeadline(a.Title, titles) {
+			continue
+		}
+		unique = append(unique, a)
+		titles = append(titles, a.Title)
+	}
+	return unique
+}
+
+// headlineWords returns the set of key words in a headline.
+func headlineWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range foldWords(title) {
+		if len(w) >= 3 && !articleStopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// similarHeadline reports whether title's key words are at least
+// duplicateSimilarity similar to one of the others', e.g. "Bitcoin tops
+// $100,000 for the first time" and "Bitcoin tops $100,000 for first time
+// ever". A short headline doesn't match a longer one just by having all its
+// words in it.
+func similarHeadline(title string, others []string) bool {
+	words := headlineWords(title)
+	if len(words) == 0 {
+		return false
+	}
+	for _, other := range others {
+		otherWords := headlineWords(other)
+		shared := 0
+		for w := range words {
+			if otherWords[w] {
+				shared++
+			}
+		}
+		union := len(words) + len(otherWords) - shared
+		if float64(shared)/float64(union) >= duplicateSimilarity {
+			return true
+		}
+	}
+	return false
+}
+
+// withArticleLink appends the article's link to text, truncating the text
+// to leave room for it within limit. X shortens every link to 23 characters.
+func withArticleLink(text string, article *NewsAPIArticle, limit int) string {
+	return truncateTweet(text, limit-tweetTextURLLength-2) + "\n\n" + article.Url
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimilarHeadline(t *testing.T) {
+	tests := []struct {
+		title string
+		other string
+		want  bool
+	}{
+		{"Bitcoin tops $100,000 for the first time", "Bitcoin tops $100,000 for first time ever", true},
+		{"Bitcoin tops $100,000 for the first time", "BITCOIN TOPS $100,000 FOR THE FIRST TIME", true},
+		{"Bitcoin tops $100,000 for the first time", "Ethereum ETF approved by the SEC", false},
+		{"Bitcoin rallies", "Bitcoin rallies as Ethereum, Solana and Cardano slump on regulation fears", false},
+		{"Bitcoin rallies", "", false},
+		{"", "Bitcoin rallies", false},
+	}
+	for _, tt := range tests {
+		if got := similarHeadline(tt.title, []string{tt.other}); got != tt.want {
+			t.Errorf("similarHeadline(%q, %q) = %v, want %v", tt.title, tt.other, got, tt.want)
+		}
+	}
+	if similarHeadline("Bitcoin rallies", nil) {
+		t.Error("similar to no headlines")
+	}
+}
+
+func TestRankArticles(t *testing.T) {
+	now := time.Now()
+	article := func(source, title string, age time.Duration) NewsAPIArticle {
+		a := NewsAPIArticle{Title: title, Url: "https://example.com/" + strings.ReplaceAll(title, " ", "-"), PublishedAt: now.Add(-age)}
+		a.Source.ID, a.Source.Name = strings.ToLower(source), source
+		return a
+	}
+	nb := &NewsBot{config: &Config{NewsSources: []string{"reuters", "coindesk"}}}
+	articles := []NewsAPIArticle{
+		article("Blog", "Solana validators vote on fee changes", time.Hour),
+		article("CoinDesk", "Bitcoin tops $100,000 for the first time", 2*time.Hour),
+		article("Blog", "Ethereum upgrade date set", 3*time.Hour),
+		article("Reuters", "Bitcoin tops $100,000 for first time ever", 3*time.Hour),
+		article("Blog", "[Removed]", 0),
+		{Title: "No link", PublishedAt: now},
+	}
+	var got []string
+	for _, a := range nb.rankArticles(articles) {
+		got = append(got, a.Source.Name+": "+a.Title)
+	}
+	want := []string{
+		"Reuters: Bitcoin tops $100,000 for first time ever",
+		"Blog: Solana validators vote on fee changes",
+		"Blog: Ethereum upgrade date set",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// fetchUpcomingMatch returns the next fixture in the league kicking off
+// within lead time of now that hasn't been previewed yet.
+func (nb *NewsBot) fetchUpcomingMatch(ctx context.Context, league string, lead time.Duration) (*PremierLeagueMatch, error) {
+	now := time.Now().UTC()
+	matches, err := fromSources(nb, ctx, league, func(s FootballDataSource, c *Competition) ([]PremierLeagueMatch, error) {
+		return s.Fixtures(ctx, c, now, now.Add(lead))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var next *PremierLeagueMatch
+	var nextKickoff time.Time
+	for i := range matches {
+		m := &matches[i]
+		kickoff := m.UtcDate
+		if kickoff.Before(now) || kickoff.Sub(now) > lead {
+			continue
+		}
+		if nb.store.HasPosted(previewKey(m)) {
+			continue
+		}
+		if next == nil || kickoff.Before(nextKickoff) {
+			next, nextKickoff = m, kickoff
+		}
+	}
+	if next == nil {
+		return nil, errNothingNew
+	}
+	return next, nil
+}
+
+// fetchTeamForm returns a team's last five results as a string such as
+// "WWDLW", oldest first. The team ID is looked up with the provider the
+// match came from.
+func (nb *NewsBot) fetchTeamForm(ctx context.Context, provider string, teamID int) (string, error) {
+	matches, err := nb.sourceFor(provider).TeamResults(ctx, teamID, 5)
+	if err != nil {
+		return "", err
+	}
+	var form strings.Builder
+	for _, m := range matches {
+		scored, conceded := m.Score.FullTime.Home, m.Score.FullTime.Away
+		if m.AwayTeam.ID == teamID {
+			scored, conceded = conceded, scored
+		}
+		switch {
+		case scored > conceded:
+			form.WriteByte('W')
+		case scored < conceded:
+			form.WriteByte('L')
+		default:
+			form.WriteByte('D')
+		}
+	}
+	return form.String(), nil
+}
+
+// generateMatchPreview writes a preview of the next fixture in the topic's
+// league, posted up to LeadTime before kickoff.
+func (nb *NewsBot) generateMatchPreview(ctx context.Context, topic *Topic) (*Draft, error) {
+	match, err := nb.fetchUpcomingMatch(ctx, topic.League, time.Duration(topic.LeadTime))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch upcoming match: %w", err)
+	}
+	return nb.writeMatchPreview(ctx, topic, match, topic.DisplayName)
+}
+
+// writeMatchPreview writes a preview of an upcoming match; subject names the
+// league or club the post is about.
+func (nb *NewsBot) writeMatchPreview(ctx context.Context, topic *Topic, match *PremierLeagueMatch, subject string) (*Draft, error) {
+	local := match.UtcDate.In(topic.location)
+	facts := fmt.Sprintf("Fixture: %s vs %s\nKick-off: %s",
+		match.HomeTeam.Name, match.AwayTeam.Name, local.Format("Mon 2 Jan, 15:04 MST"))
+	if match.Venue != "" {
+		facts += "\nVenue: " + match.Venue
+	}
+	if history := nb.matchContext(ctx, match); history != "" {
+		facts += "\n" + history
+	}
+
+	var prompt string
+	if custom := nb.customPrompt(topic); custom != "" {
+		prompt = custom + "\n\n" + facts
+	} else {
+		prompt = fmt.Sprintf(`Write a complete, engaging pre-match preview tweet (%s) for this upcoming %s fixture.\n\n%s\n\nMention the kick-off time and what's at stake, and use the recent form and head-to-head record for context. Do not predict or invent a score, and don't state any facts beyond those given. Include hashtags like %s. Output only the tweet text.`,
+			nb.lengthRequirement(topic), subject, facts, topic.hashtags())
+	}
+	gen, err := nb.generateVerified(ctx, topic, prompt, GenerateOptions{
+		SystemPrompt: footballSystemPrompt(topic.hashtags(), nb.textLimit(topic)),
+		Temperature:  0.8,
+		MaxTokens:    200,
+	}, func(text string) error { return verifyPreviewText(text, match) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s preview: %v", subject, err)
+	}
+	return &Draft{
+		Topic:     topic.Name,
+		Text:      gen.Text,
+		SourceKey: previewKey(match),
+		Provider:  gen.Provider,
+		Model:     gen.Model,
+		Source:    match,
+	}, nil
+}
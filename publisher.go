@@ -0,0 +1,494 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Post is a generated piece of content ready to be published.
+type Post struct {
+	Topic    string
+	Text     string
+	Thread   bool     // split text longer than one post into a reply thread where supported
+	Segments []string // explicit thread posts, used instead of splitting Text where supported
+	Media    []Media  // images attached to the first post where supported
+}
+
+// Media is an image attached to a post.
+type Media struct {
+	Data     []byte
+	MimeType string
+	AltText  string
+}
+
+// PublishResult is the outcome of publishing a post to one target.
+type PublishResult struct {
+	Publisher string
+	ID        string
+	Err       error
+}
+
+// Publisher is an output target for generated posts (X, Mastodon, ...).
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, post *Post) (string, error)
+}
+
+// buildPublishers constructs the publishers named in PUBLISHERS. xClient is
+// the OAuth1-signed client used for the X API.
+func buildPublishers(config *Config, xClient *http.Client) ([]Publisher, error) {
+	var publishers []Publisher
+	for _, name := range config.Publishers {
+		switch name {
+		case "x":
+			if config.XAPIKey == "" || config.XAPIKeySecret == "" ||
+				config.XAccessToken == "" || config.XAccessTokenSecret == "" {
+				return nil, fmt.Errorf("all X API credentials are required")
+			}
+			publishers = append(publishers, NewXPublisher(xClient, config.ThreadRollback, config.ThreadMaxPosts))
+		case "mastodon":
+			if config.MastodonServer == "" || config.MastodonAccessToken == "" {
+				return nil, fmt.Errorf("MASTODON_SERVER and MASTODON_ACCESS_TOKEN are required for the mastodon publisher")
+			}
+			publishers = append(publishers, NewMastodonPublisher(config.MastodonServer, config.MastodonAccessToken))
+		case "bluesky":
+			if config.BlueskyHandle == "" || config.BlueskyAppPassword == "" {
+				return nil, fmt.Errorf("BLUESKY_HANDLE and BLUESKY_APP_PASSWORD are required for the bluesky publisher")
+			}
+			publishers = append(publishers, NewBlueskyPublisher(config.BlueskyPDS, config.BlueskyHandle, config.BlueskyAppPassword))
+		case "webhook":
+			if config.WebhookURL == "" {
+				return nil, fmt.Errorf("WEBHOOK_URL is required for the webhook publisher")
+			}
+			publishers = append(publishers, NewWebhookPublisher(config.WebhookURL, config.WebhookSecret))
+		default:
+			return nil, fmt.Errorf("unknown publisher %q in PUBLISHERS", name)
+		}
+	}
+	if len(publishers) == 0 {
+		return nil, fmt.Errorf("no publishers configured")
+	}
+	return publishers, nil
+}
+
+// publish fans the post out to every configured publisher and reports the
+// outcome per target. It only fails when nothing went live on any target; a
+// partially posted thread counts as posted so it is recorded.
+func (nb *NewsBot) publish(ctx context.Context, post *Post) ([]PublishResult, error) {
+	results := make([]PublishResult, 0, len(nb.publishers))
+	succeeded := 0
+	for _, p := range nb.publishers {
+		log.Printf("Posting to %s...", p.Name())
+		id, err := p.Publish(ctx, post)
+		results = append(results, PublishResult{Publisher: p.Name(), ID: id, Err: err})
+		if err != nil {
+			log.Printf("Failed to post to %s: %v", p.Name(), err)
+			if id != "" {
+				succeeded++
+			}
+			continue
+		}
+		succeeded++
+		log.Printf("Posted to %s with ID: %s", p.Name(), id)
+	}
+	if succeeded == 0 {
+		return results, fmt.Errorf("all %d publishers failed", len(results))
+	}
+	if succeeded < len(results) {
+		log.Printf("Posted to %d of %d targets", succeeded, len(results))
+	}
+	return results, nil
+}
+
+// XPublisher posts to X via the API v2 tweets endpoint. Thread posts longer
+// than one tweet are split into numbered replies, and explicit segments are
+// posted as a reply thread as they are.
+type XPublisher struct {
+	client   *http.Client
+	rollback bool // delete already-posted tweets when a thread fails part way
+	maxPosts int  // longest thread a post is split into
+}
+
+func NewXPublisher(client *http.Client, rollback bool, maxPosts int) *XPublisher {
+	return &XPublisher{client: client, rollback: rollback, maxPosts: maxPosts}
+}
+
+func (x *XPublisher) Name() string { return "x" }
+
+// Publish posts the text, as a thread if needed, and returns the ID of the
+// first tweet. Media is uploaded first and attached to the first tweet; if
+// the upload fails the post goes out as text only. If a thread fails part
+// way the posted tweets are deleted when rollback is enabled; otherwise, or
+// if a delete fails, the first remaining ID is returned together with a
+// *PartialThreadError listing the tweets still live.
+func (x *XPublisher) Publish(ctx context.Context, post *Post) (string, error) {
+	segments := []string{post.Text}
+	switch {
+	case len(post.Segments) > 0:
+		segments = post.Segments
+	case post.Thread:
+		segments = splitThread(post.Text, maxTweetLength, x.maxPosts)
+	}
+
+	var mediaIDs []string
+	for _, m := range post.Media {
+		id, err := x.uploadMedia(ctx, m)
+		if err != nil {
+			log.Printf("Failed to upload media, posting without it: %v", err)
+			mediaIDs = nil
+			break
+		}
+		mediaIDs = append(mediaIDs, id)
+	}
+
+	var ids []string
+	for i, segment := range segments {
+		replyTo := ""
+		var media []string
+		if i > 0 {
+			replyTo = ids[i-1]
+		} else {
+			media = mediaIDs
+		}
+		id, err := x.createTweet(ctx, segment, replyTo, media)
+		if err == nil {
+			ids = append(ids, id)
+			continue
+		}
+		if len(ids) == 0 {
+			return "", err
+		}
+		partial := &PartialThreadError{PostedIDs: ids, Total: len(segments), Err: err}
+		if !x.rollback {
+			return ids[0], partial
+		}
+		log.Printf("Rolling back partial thread: %v", partial)
+		// Delete replies first so the thread never has a gap.
+		for remaining := ids; len(remaining) > 0; remaining = remaining[:len(remaining)-1] {
+			last := remaining[len(remaining)-1]
+			if derr := x.deleteTweet(ctx, last); derr != nil {
+				log.Printf("Failed to delete tweet %s: %v", last, derr)
+				partial.PostedIDs = remaining
+				return remaining[0], partial
+			}
+		}
+		return "", fmt.Errorf("thread failed at post %d of %d and was rolled back: %v", i+1, len(segments), err)
+	}
+	if len(ids) > 1 {
+		log.Printf("Posted thread of %d tweets", len(ids))
+	}
+	return ids[0], nil
+}
+
+func (x *XPublisher) createTweet(ctx context.Context, text, replyTo string, mediaIDs []string) (string, error) {
+	url := "https://api.twitter.com/2/tweets"
+	tweetReq := TweetRequest{Text: text}
+	if replyTo != "" {
+		tweetReq.Reply = &TweetReply{InReplyToTweetID: replyTo}
+	}
+	if len(mediaIDs) > 0 {
+		tweetReq.Media = &TweetMedia{MediaIDs: mediaIDs}
+	}
+
+	jsonData, err := json.Marshal(tweetReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tweet request: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	// OAuth1 client handles auth automatically
+
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %v", err)
+	}
+	log.Printf("Raw Response: %s", string(body)) // Debug raw response
+
+	var tweetResp TweetResponse
+	if err := json.Unmarshal(body, &tweetResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %v, raw response: %s", err, string(body))
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		if len(tweetResp.Errors) > 0 {
+			return "", fmt.Errorf("X API error (status %d): %s", resp.StatusCode, tweetResp.Errors[0].Message)
+		}
+		return "", fmt.Errorf("X API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return tweetResp.Data.ID, nil
+}
+
+func (x *XPublisher) deleteTweet(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", "https://api.twitter.com/2/tweets/"+id, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	resp, err := x.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("X API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// Single-post limits on the other networks. Threads are only split on X;
+// elsewhere long posts are shortened to fit.
+const (
+	mastodonMaxLength = 500
+	blueskyMaxLength  = 300
+)
+
+// MastodonPublisher posts a status to a Mastodon instance.
+type MastodonPublisher struct {
+	server      string
+	accessToken string
+	client      *http.Client
+}
+
+func NewMastodonPublisher(server, accessToken string) *MastodonPublisher {
+	return &MastodonPublisher{
+		server:      strings.TrimRight(server, "/"),
+		accessToken: accessToken,
+		client:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (m *MastodonPublisher) Name() string { return "mastodon" }
+
+func (m *MastodonPublisher) Publish(ctx context.Context, post *Post) (string, error) {
+	jsonData, err := json.Marshal(map[string]string{
+		"status":     truncateGraphemes(post.Text, mastodonMaxLength),
+		"visibility": "public",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal status: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", m.server+"/api/v1/statuses", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.accessToken)
+	// Mastodon deduplicates retried requests carrying the same key.
+	sum := sha256.Sum256([]byte(post.Text))
+	req.Header.Set("Idempotency-Key", hex.EncodeToString(sum[:16]))
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Mastodon API: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("Mastodon API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	var status struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return "", fmt.Errorf("failed to decode Mastodon response: %v", err)
+	}
+	return status.ID, nil
+}
+
+// BlueskyPublisher posts to Bluesky through the AT Protocol XRPC API.
+type BlueskyPublisher struct {
+	pds         string
+	handle      string
+	appPassword string
+	client      *http.Client
+}
+
+func NewBlueskyPublisher(pds, handle, appPassword string) *BlueskyPublisher {
+	return &BlueskyPublisher{
+		pds:         strings.TrimRight(pds, "/"),
+		handle:      handle,
+		appPassword: appPassword,
+		client:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (b *BlueskyPublisher) Name() string { return "bluesky" }
+
+type blueskySession struct {
+	AccessJwt string `json:"accessJwt"`
+	DID       string `json:"did"`
+}
+
+type blueskyFacet struct {
+	Index struct {
+		ByteStart int `json:"byteStart"`
+		ByteEnd   int `json:"byteEnd"`
+	} `json:"index"`
+	Features []map[string]string `json:"features"`
+}
+
+func (b *BlueskyPublisher) xrpc(ctx context.Context, method, token string, in, out interface{}) error {
+	jsonData, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %v", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", b.pds+"/xrpc/"+method, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %v", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Bluesky API error on %s (status %d): %s", method, resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %v", method, err)
+	}
+	return nil
+}
+
+func (b *BlueskyPublisher) Publish(ctx context.Context, post *Post) (string, error) {
+	var session blueskySession
+	err := b.xrpc(ctx, "com.atproto.server.createSession", "", map[string]string{
+		"identifier": b.handle,
+		"password":   b.appPassword,
+	}, &session)
+	if err != nil {
+		return "", err
+	}
+
+	text := truncateGraphemes(post.Text, blueskyMaxLength)
+	record := map[string]interface{}{
+		"$type":     "app.bsky.feed.post",
+		"text":      text,
+		"createdAt": time.Now().UTC().Format(time.RFC3339),
+	}
+	if facets := blueskyFacets(text); len(facets) > 0 {
+		record["facets"] = facets
+	}
+
+	var created struct {
+		URI string `json:"uri"`
+		CID string `json:"cid"`
+	}
+	err = b.xrpc(ctx, "com.atproto.repo.createRecord", session.AccessJwt, map[string]interface{}{
+		"repo":       session.DID,
+		"collection": "app.bsky.feed.post",
+		"record":     record,
+	}, &created)
+	if err != nil {
+		return "", err
+	}
+	return created.URI, nil
+}
+
+var (
+	blueskyTagRe  = regexp.MustCompile(`(^|\s)#([\pL\pN_]+)`)
+	blueskyLinkRe = regexp.MustCompile(`https?://[^\s]+`)
+)
+
+// blueskyFacets marks hashtags and links so Bluesky renders them as rich
+// text. Facet indexes are UTF-8 byte offsets.
+func blueskyFacets(text string) []blueskyFacet {
+	var facets []blueskyFacet
+	for _, m := range blueskyTagRe.FindAllStringSubmatchIndex(text, -1) {
+		var f blueskyFacet
+		f.Index.ByteStart = m[4] - 1 // include the '#'
+		f.Index.ByteEnd = m[5]
+		f.Features = []map[string]string{{"$type": "app.bsky.richtext.facet#tag", "tag": text[m[4]:m[5]]}}
+		facets = append(facets, f)
+	}
+	for _, m := range blueskyLinkRe.FindAllStringIndex(text, -1) {
+		var f blueskyFacet
+		f.Index.ByteStart = m[0]
+		f.Index.ByteEnd = m[1]
+		f.Features = []map[string]string{{"$type": "app.bsky.richtext.facet#link", "uri": text[m[0]:m[1]]}}
+		facets = append(facets, f)
+	}
+	return facets
+}
+
+// WebhookPublisher POSTs the post as JSON to an arbitrary URL. When a secret
+// is configured the body is signed with HMAC-SHA256 in X-Signature-256.
+type WebhookPublisher struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookPublisher(url, secret string) *WebhookPublisher {
+	return &WebhookPublisher{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (w *WebhookPublisher) Name() string { return "webhook" }
+
+func (w *WebhookPublisher) Publish(ctx context.Context, post *Post) (string, error) {
+	jsonData, err := json.Marshal(map[string]string{
+		"topic":      post.Topic,
+		"text":       post.Text,
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", w.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(jsonData)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("webhook error (status %d): %s", resp.StatusCode, string(body))
+	}
+	// Receivers may optionally echo an ID back.
+	var ack struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(body, &ack)
+	return ack.ID, nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// redirectTransport sends every request to a test server, whatever host it
+// was addressed to, for publishers with fixed API URLs.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = rt.target.Scheme, rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// redirectedClient returns a client whose requests all go to srv.
+func redirectedClient(srv *httptest.Server) *http.Client {
+	target, _ := url.Parse(srv.URL)
+	return &http.Client{Transport: redirectTransport{target}}
+}
+
+func TestXPublisher(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/2/tweets" {
+			http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"data":{"id":"1850000000000000001","text":"Full time"}}`))
+	}))
+	defer srv.Close()
+
+	id, err := NewXPublisher(redirectedClient(srv), false, 0).Publish(context.Background(), &Post{Text: "Full time"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "1850000000000000001" {
+		t.Errorf("ID = %q", id)
+	}
+	if body["text"] != "Full time" {
+		t.Errorf("request body %v", body)
+	}
+}
+
+func TestXPublisherError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":[{"message":"duplicate content"}]}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewXPublisher(redirectedClient(srv), false, 0).Publish(context.Background(), &Post{Text: "Full time"})
+	if err == nil || !strings.Contains(err.Error(), "duplicate content") {
+		t.Errorf("error = %v, want the API's message", err)
+	}
+}
+
+func TestXPublisherRollbackFailure(t *testing.T) {
+	posted, deleted := 0, []string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && posted < 2:
+			posted++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"data":{"id":"%d"}}`, posted)
+		case r.Method == "POST":
+			http.Error(w, `{"title":"Too Many Requests"}`, http.StatusTooManyRequests)
+		case r.Method == "DELETE" && strings.HasSuffix(r.URL.Path, "/2"):
+			deleted = append(deleted, "2")
+			w.Write([]byte(`{"data":{"deleted":true}}`))
+		default:
+			http.Error(w, `{"title":"Service Unavailable"}`, http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	post := &Post{Segments: []string{"One 1/3", "Two 2/3", "Three 3/3"}}
+	id, err := NewXPublisher(redirectedClient(srv), true, 0).Publish(context.Background(), post)
+	var partial *PartialThreadError
+	if !errors.As(err, &partial) {
+		t.Fatalf("error = %v, want a *PartialThreadError", err)
+	}
+	if id != "1" || strings.Join(partial.PostedIDs, ",") != "1" {
+		t.Errorf("ID = %q, posted IDs = %v; want only the undeleted first tweet", id, partial.PostedIDs)
+	}
+	if strings.Join(deleted, ",") != "2" {
+		t.Errorf("deleted %v, want the reply", deleted)
+	}
+}
+
+func TestMastodonPublisher(t *testing.T) {
+	var body map[string]string
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/api/v1/statuses" {
+			http.Error(w, "unexpected "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		header = r.Header
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"id":"113000000000000001"}`))
+	}))
+	defer srv.Close()
+
+	id, err := NewMastodonPublisher(srv.URL+"/", "token").Publish(context.Background(), &Post{Text: "Full time #WOLLIV"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "113000000000000001" {
+		t.Errorf("ID = %q", id)
+	}
+	if body["status"] != "Full time #WOLLIV" || body["visibility"] != "public" {
+		t.Errorf("request body %v", body)
+	}
+	if header.Get("Authorization") != "Bearer token" || header.Get("Idempotency-Key") == "" {
+		t.Errorf("headers %v", header)
+	}
+}
+
+func TestBlueskyPublisher(t *testing.T) {
+	var session map[string]string
+	var record struct {
+		Auth string
+		Repo string `json:"repo"`
+		Coll string `json:"collection"`
+		Rec  struct {
+			Type   string         `json:"$type"`
+			Text   string         `json:"text"`
+			Facets []blueskyFacet `json:"facets"`
+		} `json:"record"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			json.NewDecoder(r.Body).Decode(&session)
+			w.Write([]byte(`{"accessJwt":"jwt","did":"did:plc:bot"}`))
+		case "/xrpc/com.atproto.repo.createRecord":
+			json.NewDecoder(r.Body).Decode(&record)
+			record.Auth = r.Header.Get("Authorization")
+			w.Write([]byte(`{"uri":"at://did:plc:bot/app.bsky.feed.post/3k","cid":"bafy"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	text := "Full time #WOLLIV https://example.com/report"
+	uri, err := NewBlueskyPublisher(srv.URL, "bot.bsky.social", "app-pass").Publish(context.Background(), &Post{Text: text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri != "at://did:plc:bot/app.bsky.feed.post/3k" {
+		t.Errorf("URI = %q", uri)
+	}
+	if session["identifier"] != "bot.bsky.social" || session["password"] != "app-pass" {
+		t.Errorf("session request %v", session)
+	}
+	if record.Auth != "Bearer jwt" || record.Repo != "did:plc:bot" || record.Coll != "app.bsky.feed.post" {
+		t.Errorf("createRecord request %+v", record)
+	}
+	if record.Rec.Type != "app.bsky.feed.post" || record.Rec.Text != text {
+		t.Errorf("record %+v", record.Rec)
+	}
+	if len(record.Rec.Facets) != 2 {
+		t.Fatalf("got %d facets, want 2", len(record.Rec.Facets))
+	}
+	for _, f := range record.Rec.Facets {
+		span := text[f.Index.ByteStart:f.Index.ByteEnd]
+		if span != "#WOLLIV" && span != "https://example.com/report" {
+			t.Errorf("facet covers %q", span)
+		}
+	}
+}
+
+func TestBlueskyFacetsByteOffsets(t *testing.T) {
+	text := "Müller ⚽ #Bayern"
+	facets := blueskyFacets(text)
+	if len(facets) != 1 {
+		t.Fatalf("got %d facets, want 1", len(facets))
+	}
+	if span := text[facets[0].Index.ByteStart:facets[0].Index.ByteEnd]; span != "#Bayern" {
+		t.Errorf("facet covers %q", span)
+	}
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var body []byte
+	var signature string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Signature-256")
+		w.Write([]byte(`{"id":"42"}`))
+	}))
+	defer srv.Close()
+
+	id, err := NewWebhookPublisher(srv.URL, "s3cret").Publish(context.Background(), &Post{Topic: "premier_league", Text: "Full time"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "42" {
+		t.Errorf("ID = %q", id)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["topic"] != "premier_league" || payload["text"] != "Full time" || payload["created_at"] == "" {
+		t.Errorf("payload %v", payload)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("signature %q, want %q", signature, want)
+	}
+}
+
+func TestWebhookPublisherRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	if _, err := NewWebhookPublisher(srv.URL, "").Publish(context.Background(), &Post{Text: "x"}); err == nil {
+		t.Error("accepted a 400 response")
+	}
+}
+
+// fakePublisher returns a canned ID or error.
+type fakePublisher struct {
+	name string
+	id   string
+	err  error
+}
+
+func (p *fakePublisher) Name() string { return p.name }
+
+func (p *fakePublisher) Publish(ctx context.Context, post *Post) (string, error) {
+	return p.id, p.err
+}
+
+func TestPublishFansOut(t *testing.T) {
+	nb := &NewsBot{publishers: []Publisher{
+		&fakePublisher{name: "x", err: errors.New("rate limited")},
+		&fakePublisher{name: "mastodon", id: "1"},
+	}}
+	results, err := nb.publish(context.Background(), &Post{Text: "hi"})
+	if err != nil {
+		t.Fatalf("failed although one target succeeded: %v", err)
+	}
+	if len(results) != 2 || results[0].Err == nil || results[1].ID != "1" {
+		t.Errorf("results %+v", results)
+	}
+
+	nb.publishers = nb.publishers[:1]
+	if _, err := nb.publish(context.Background(), &Post{Text: "hi"}); err == nil {
+		t.Error("succeeded with every target failing")
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces requests evenly so at most perMinute are made in any
+// minute, and backs off when the server reports the quota is used up.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(perMinute int) *rateLimiter {
+	return &rateLimiter{interval: time.Minute / time.Duration(perMinute)}
+}
+
+// Wait blocks until the next request may be made.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	if d := time.Until(at); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// Observe reads football-data.org's quota headers and holds further requests
+// until the counter resets when none are left.
+func (l *rateLimiter) Observe(resp *http.Response) {
+	if resp.Header.Get("X-Requests-Available-Minute") != "0" && resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	reset, err := strconv.Atoi(resp.Header.Get("X-RequestCounter-Reset"))
+	if err != nil || reset <= 0 {
+		reset = 60
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(time.Duration(reset) * time.Second); until.After(l.next) {
+		l.next = until
+	}
+}
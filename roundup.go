@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CompetitionResponse is the football-data.org /competitions/{code} response.
+type CompetitionResponse struct {
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	CurrentSeason struct {
+		StartDate       string `json:"startDate"`
+		CurrentMatchday int    `json:"currentMatchday"`
+	} `json:"currentSeason"`
+}
+
+// Round is every match of one matchday in a competition.
+type Round struct {
+	Code     string
+	Season   string // year the season started, e.g. "2025"
+	Matchday int
+	Matches  []PremierLeagueMatch
+}
+
+func (r *Round) key() string {
+	return fmt.Sprintf("roundup:%s:%s:%d", r.Code, r.Season, r.Matchday)
+}
+
+// complete reports whether every match in the round has been played or
+// called off, with at least one result to post.
+func (r *Round) complete() bool {
+	played := 0
+	for _, m := range r.Matches {
+		switch m.Status {
+		case "FINISHED", "AWARDED":
+			played++
+		case "POSTPONED", "CANCELLED", "SUSPENDED":
+		default:
+			return false
+		}
+	}
+	return played > 0
+}
+
+// lastKickoff is the kick-off of the round's last match.
+func (r *Round) lastKickoff() time.Time {
+	var last time.Time
+	for _, m := range r.Matches {
+		if m.UtcDate.After(last) {
+			last = m.UtcDate
+		}
+	}
+	return last
+}
+
+// fetchCompletedRound returns the most recently completed matchday in the
+// league that hasn't had a roundup yet. The current matchday and the one
+// before are checked, since football-data.org moves on to the next matchday
+// as soon as its first match is played.
+func (nb *NewsBot) fetchCompletedRound(ctx context.Context, league string) (*Round, error) {
+	var comp CompetitionResponse
+	if err := nb.footballData(ctx, "/competitions/"+league, &comp); err != nil {
+		return nil, err
+	}
+	season := comp.CurrentSeason.StartDate[:min(4, len(comp.CurrentSeason.StartDate))]
+	current := comp.CurrentSeason.CurrentMatchday
+	for _, md := range []int{current - 1, current} {
+		if md < 1 {
+			continue
+		}
+		round := &Round{Code: league, Season: season, Matchday: md}
+		if nb.store.HasPosted(round.key()) {
+			continue
+		}
+		var resp PremierLeagueMatchesResponse
+		if err := nb.footballData(ctx, fmt.Sprintf("/competitions/%s/matches?matchday=%d", league, md), &resp); err != nil {
+			return nil, err
+		}
+		round.Matches = resp.Matches
+		sortByKickoff(round.Matches)
+		// Don't dig up a round that finished long ago, e.g. when the topic
+		// is first switched on.
+		if round.complete() && time.Since(round.lastKickoff()) < resultsWindow {
+			return round, nil
+		}
+	}
+	return nil, errNothingNew
+}
+
+// resultLine is a round's result as listed in a roundup, e.g.
+// "Arsenal 2-1 Chelsea".
+func resultLine(m *PremierLeagueMatch) string {
+	switch m.Status {
+	case "FINISHED", "AWARDED":
+		return fmt.Sprintf("%s %d-%d %s", teamLabel(m.HomeTeam), m.Score.FullTime.Home, m.Score.FullTime.Away, teamLabel(m.AwayTeam))
+	default:
+		return fmt.Sprintf("%s v %s (%s)", teamLabel(m.HomeTeam), teamLabel(m.AwayTeam), strings.ToLower(m.Status))
+	}
+}
+
+// generateRoundup posts a thread covering every result of the league's last
+// completed matchday: a headline post with a short narrative intro, then the
+// results, as many to a post as fit.
+func (nb *NewsBot) generateRoundup(ctx context.Context, topic *Topic) (*Draft, error) {
+	round, err := nb.fetchCompletedRound(ctx, topic.League)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch matchday: %w", err)
+	}
+
+	var lines []string
+	var played []*PremierLeagueMatch
+	for i := range round.Matches {
+		m := &round.Matches[i]
+		lines = append(lines, resultLine(m))
+		if m.Status == "FINISHED" || m.Status == "AWARDED" {
+			played = append(played, m)
+		}
+	}
+	headline := fmt.Sprintf("📋 %s matchday %d roundup", topic.DisplayName, round.Matchday)
+	// Room for the intro in the first post, around the headline, hashtags
+	// and " 1/N" numbering.
+	budget := maxTweetLength - tweetWeightedLength(headline+"\n\n\n\n"+topic.hashtags()) - len(" 99/99")
+
+	facts := fmt.Sprintf("%s matchday %d results:\n%s", topic.DisplayName, round.Matchday, strings.Join(lines, "\n"))
+	prompt := fmt.Sprintf(`Write a short narrative intro (under %d characters) for a thread rounding up this %s matchday.\n\n%s\n\nPick out the standout results and any theme across the round, using only the scores given. Don't list every result, and don't include hashtags. Output only the intro text.`,
+		budget, topic.DisplayName, facts)
+	if custom := nb.customPrompt(topic); custom != "" {
+		prompt = custom + "\n\n" + facts
+	}
+	gen, err := nb.generateVerified(ctx, topic, prompt, GenerateOptions{
+		SystemPrompt: footballSystemPrompt(topic.hashtags(), budget),
+		Temperature:  0.8,
+		MaxTokens:    150,
+	}, func(text string) error { return verifyRoundupText(text, played) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s roundup: %v", topic.DisplayName, err)
+	}
+	intro := truncateTweet(gen.Text, budget)
+
+	first := headline + "\n\n" + intro
+	if tags := topic.hashtags(); tags != "" {
+		first += "\n\n" + tags
+	}
+	segments := append([]string{first}, packLines(lines, maxTweetLength-len(" 99/99"))...)
+	for i := range segments {
+		segments[i] = fmt.Sprintf("%s %d/%d", segments[i], i+1, len(segments))
+	}
+
+	return &Draft{
+		Topic:     topic.Name,
+		Text:      first + "\n\n" + strings.Join(lines, "\n"),
+		Segments:  segments,
+		SourceKey: round.key(),
+		Provider:  gen.Provider,
+		Model:     gen.Model,
+		Source:    round,
+	}, nil
+}
+
+// packLines joins lines into posts of at most budget weighted characters,
+// one line per row.
+func packLines(lines []string, budget int) []string {
+	var posts []string
+	current := ""
+	for _, line := range lines {
+		candidate := line
+		if current != "" {
+			candidate = current + "\n" + line
+		}
+		if current != "" && tweetWeightedLength(candidate) > budget {
+			posts = append(posts, current)
+			candidate = line
+		}
+		current = candidate
+	}
+	if current != "" {
+		posts = append(posts, current)
+	}
+	return posts
+}
+
+// verifyRoundupText checks that every scoreline quoted in a roundup intro is
+// one of the round's results.
+func verifyRoundupText(text string, played []*PremierLeagueMatch) error {
+	for _, m := range scorelineRe.FindAllStringSubmatch(text, -1) {
+		a, b := m[1], m[2]
+		found := false
+		for _, p := range played {
+			home, away := fmt.Sprint(p.Score.FullTime.Home), fmt.Sprint(p.Score.FullTime.Away)
+			if (a == home && b == away) || (a == away && b == home) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("it quotes a %s-%s scoreline that isn't one of the results", a, b)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoundComplete(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     bool
+	}{
+		{[]string{"FINISHED", "FINISHED"}, true},
+		{[]string{"FINISHED", "POSTPONED"}, true},
+		{[]string{"AWARDED", "CANCELLED"}, true},
+		{[]string{"FINISHED", "TIMED"}, false},
+		{[]string{"FINISHED", "IN_PLAY"}, false},
+		{[]string{"POSTPONED"}, false},
+	}
+	for _, tt := range tests {
+		round := &Round{}
+		for _, s := range tt.statuses {
+			round.Matches = append(round.Matches, PremierLeagueMatch{Status: s})
+		}
+		if got := round.complete(); got != tt.want {
+			t.Errorf("complete() with %v = %v, want %v", tt.statuses, got, tt.want)
+		}
+	}
+}
+
+func roundupMatch(home, away string, homeGoals, awayGoals int, status string) *PremierLeagueMatch {
+	m := &PremierLeagueMatch{
+		HomeTeam: MatchTeam{Name: home + " FC", ShortName: home},
+		AwayTeam: MatchTeam{Name: away + " FC", ShortName: away},
+		Status:   status,
+	}
+	m.Score.FullTime.Home, m.Score.FullTime.Away = homeGoals, awayGoals
+	return m
+}
+
+func TestResultLine(t *testing.T) {
+	if got, want := resultLine(roundupMatch("Arsenal", "Chelsea", 2, 1, "FINISHED")), "Arsenal 2-1 Chelsea"; got != want {
+		t.Errorf("resultLine = %q, want %q", got, want)
+	}
+	if got, want := resultLine(roundupMatch("Everton", "Fulham", 0, 0, "POSTPONED")), "Everton v Fulham (postponed)"; got != want {
+		t.Errorf("resultLine = %q, want %q", got, want)
+	}
+}
+
+func TestPackLines(t *testing.T) {
+	lines := []string{"Arsenal 2-1 Chelsea", "Everton 0-0 Fulham", "Spurs 3-2 Wolves"}
+	got := packLines(lines, 40)
+	want := []string{"Arsenal 2-1 Chelsea\nEverton 0-0 Fulham", "Spurs 3-2 Wolves"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("packLines = %q, want %q", got, want)
+	}
+	if got := packLines(lines, maxTweetLength); len(got) != 1 {
+		t.Errorf("packLines with room for everything = %q, want one post", got)
+	}
+}
+
+func TestVerifyRoundupText(t *testing.T) {
+	played := []*PremierLeagueMatch{
+		roundupMatch("Arsenal", "Chelsea", 2, 1, "FINISHED"),
+		roundupMatch("Spurs", "Wolves", 0, 3, "FINISHED"),
+	}
+	for _, text := range []string{
+		"Arsenal edged Chelsea 2-1 while Wolves won 3-0 at Spurs",
+		"A derby decided by a late winner",
+	} {
+		if err := verifyRoundupText(text, played); err != nil {
+			t.Errorf("%q: unexpected error: %v", text, err)
+		}
+	}
+	if err := verifyRoundupText("Arsenal thrashed Chelsea 4-0", played); err == nil {
+		t.Error("accepted a scoreline that isn't one of the results")
+	}
+}
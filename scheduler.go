@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// Serve keeps the bot running, firing the topic rotation on SCHEDULE and
+// each topic that has its own schedule on that cron expression. Scheduled
+// topics still respect their days, hours and cooldown. Jobs run one at a
+// time, so minutes that pass while a long job runs are skipped, not caught
+// up. When ctx is cancelled the in-flight job is allowed to finish before
+// Serve returns.
+func (nb *NewsBot) Serve(ctx context.Context) error {
+	var rotation *CronSchedule
+	if nb.config.Schedule != "" {
+		var err error
+		if rotation, err = ParseCron(nb.config.Schedule); err != nil {
+			return fmt.Errorf("invalid SCHEDULE: %v", err)
+		}
+	}
+
+	// Topics with their own schedule are only posted on that schedule.
+	var rotationTopics, scheduledTopics []*Topic
+	for _, t := range nb.topics {
+		if t.schedule != nil {
+			scheduledTopics = append(scheduledTopics, t)
+			log.Printf("Topic %s scheduled at %q", t.Name, t.schedule)
+		} else {
+			rotationTopics = append(rotationTopics, t)
+		}
+	}
+	if rotation == nil && len(scheduledTopics) == 0 {
+		return fmt.Errorf("nothing to schedule: set SCHEDULE or give topics a schedule")
+	}
+	if rotation != nil {
+		log.Printf("Topic rotation scheduled at %q across %d topics", rotation, len(rotationTopics))
+	}
+
+	log.Printf("Scheduler started (job timeout %s)", nb.config.JobTimeout)
+	for {
+		next := time.Now().Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Println("Scheduler stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		if rotation != nil && rotation.Matches(next.In(time.Local)) {
+			nb.runJob("rotation", func(jobCtx context.Context) error {
+				return nb.runRotation(jobCtx, rotationTopics)
+			})
+		}
+		for _, t := range scheduledTopics {
+			if !t.schedule.Matches(next.In(t.location)) {
+				continue
+			}
+			if !nb.topicAllowed(t, next) {
+				log.Printf("Topic %s is outside its days, hours or cooldown, skipping", t.Name)
+				continue
+			}
+			topic := t
+			nb.runJob(topic.Name, func(jobCtx context.Context) error {
+				err := nb.runTopic(jobCtx, topic)
+				if errors.Is(err, errNothingNew) {
+					log.Printf("Nothing new for %s, skipping", topic.DisplayName)
+					return nil
+				}
+				return err
+			})
+		}
+	}
+}
+
+// runJob runs fn with the configured timeout, logging rather than
+// propagating failures and panics so one bad job can't stop the scheduler.
+// The job context is deliberately not derived from the shutdown signal so an
+// in-flight post completes during graceful shutdown.
+func (nb *NewsBot) runJob(name string, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nb.config.JobTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job %s panicked: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+
+	start := time.Now()
+	log.Printf("Job %s started", name)
+	if err := fn(ctx); err != nil {
+		log.Printf("Job %s failed after %s: %v", name, time.Since(start).Round(time.Second), err)
+		return
+	}
+	log.Printf("Job %s finished in %s", name, time.Since(start).Round(time.Second))
+}
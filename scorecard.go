@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Score card layout, sized for X's 16:9 in-feed image preview.
+const (
+	cardWidth  = 1200
+	cardHeight = 675
+	badgeSize  = 112
+)
+
+var (
+	cardBackground   = color.RGBA{0x12, 0x16, 0x1f, 0xff}
+	cardForeground   = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	cardMuted        = color.RGBA{0xa0, 0xa8, 0xb8, 0xff}
+	defaultTeamColor = TeamColours{Primary: "#2d3748", Secondary: "#ffffff"}
+)
+
+// TeamColours are a club's kit colours as "#rrggbb" hex strings.
+type TeamColours struct {
+	Primary   string `json:"primary"`
+	Secondary string `json:"secondary"`
+}
+
+// loadTeamColours reads the team name -> colours map. A missing file isn't
+// an error; every team then gets the default colours.
+func loadTeamColours(path string) (map[string]TeamColours, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]TeamColours{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team colours file: %v", err)
+	}
+	var colours map[string]TeamColours
+	if err := json.Unmarshal(raw, &colours); err != nil {
+		return nil, fmt.Errorf("failed to parse team colours file %s: %v", path, err)
+	}
+	for team, c := range colours {
+		if _, err := parseHexColor(c.Primary); err != nil {
+			return nil, fmt.Errorf("team colours for %s: %v", team, err)
+		}
+		if _, err := parseHexColor(c.Secondary); err != nil {
+			return nil, fmt.Errorf("team colours for %s: %v", team, err)
+		}
+	}
+	return colours, nil
+}
+
+// teamColours looks a team up by exact name, then ignoring case.
+func (nb *NewsBot) teamColours(team string) TeamColours {
+	if c, ok := nb.colours[team]; ok {
+		return c
+	}
+	for name, c := range nb.colours {
+		if strings.EqualFold(name, team) {
+			return c
+		}
+	}
+	return defaultTeamColor
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	var c color.RGBA
+	if _, err := fmt.Sscanf(strings.TrimPrefix(s, "#"), "%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("invalid colour %q, expected \"#rrggbb\"", s)
+	}
+	c.A = 0xff
+	return c, nil
+}
+
+func mustHexColor(s string) color.RGBA {
+	c, err := parseHexColor(s)
+	if err != nil {
+		return color.RGBA{0x80, 0x80, 0x80, 0xff}
+	}
+	return c
+}
+
+// ScoreCard is everything drawn on a result image.
+type ScoreCard struct {
+	Competition     string
+	CompetitionCode string
+	Date            time.Time
+	HomeTeam        string
+	AwayTeam        string
+	HomeScore       int
+	AwayScore       int
+	HomeColours     TeamColours
+	AwayColours     TeamColours
+	Badge           image.Image // optional competition badge; nil draws the code instead
+}
+
+// Render draws the card and encodes it as PNG.
+func (sc *ScoreCard) Render() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, cardHeight))
+	fill(img, img.Bounds(), cardBackground)
+
+	// Each side of the card is a panel in the team's primary colour with its
+	// name in the secondary colour; the score sits in the dark centre band.
+	panelWidth := cardWidth * 3 / 8
+	panelTop := 200
+	panelBottom := cardHeight - 90
+	home := image.Rect(0, panelTop, panelWidth, panelBottom)
+	away := image.Rect(cardWidth-panelWidth, panelTop, cardWidth, panelBottom)
+	fill(img, home, mustHexColor(sc.HomeColours.Primary))
+	fill(img, away, mustHexColor(sc.AwayColours.Primary))
+	drawTeamName(img, sc.HomeTeam, home, mustHexColor(sc.HomeColours.Secondary))
+	drawTeamName(img, sc.AwayTeam, away, mustHexColor(sc.AwayColours.Secondary))
+
+	score := fmt.Sprintf("%d-%d", sc.HomeScore, sc.AwayScore)
+	scale := fitScale(score, cardWidth-2*panelWidth-40, 22)
+	drawCentred(img, score, cardWidth/2, (panelTop+panelBottom)/2, scale, cardForeground)
+
+	// Competition badge slot, top centre, with the competition name beside it.
+	slot := image.Rect((cardWidth-badgeSize)/2, 40, (cardWidth+badgeSize)/2, 40+badgeSize)
+	if sc.Badge != nil {
+		drawScaled(img, slot, sc.Badge)
+	} else {
+		fill(img, slot, cardMuted)
+		inner := slot.Inset(4)
+		fill(img, inner, cardBackground)
+		code := sc.CompetitionCode
+		drawCentred(img, code, cardWidth/2, slot.Min.Y+badgeSize/2, fitScale(code, badgeSize-24, 6), cardForeground)
+	}
+	nameScale := fitScale(sc.Competition, (cardWidth-badgeSize)/2-80, 4)
+	drawText(img, sc.Competition, slot.Max.X+30, slot.Min.Y+(badgeSize-glyphHeight*nameScale)/2, nameScale, cardForeground)
+
+	if !sc.Date.IsZero() {
+		drawCentred(img, sc.Date.Format("2 Jan 2006"), cardWidth/2, cardHeight-45, 4, cardMuted)
+	}
+	drawText(img, "FULL TIME", 40, 40+(badgeSize-glyphHeight*4)/2, 4, cardMuted)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode score card: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawCentred draws s centred on (cx, cy).
+func drawCentred(img draw.Image, s string, cx, cy, scale int, c color.Color) {
+	if scale == 0 {
+		return
+	}
+	drawText(img, s, cx-textWidth(s, scale)/2, cy-glyphHeight*scale/2, scale, c)
+}
+
+// drawTeamName centres a team name in its panel, on two lines if it is too
+// long to be legible on one.
+func drawTeamName(img draw.Image, name string, panel image.Rectangle, c color.Color) {
+	width := panel.Dx() - 48
+	cx, cy := panel.Min.X+panel.Dx()/2, panel.Min.Y+panel.Dy()/2
+	if scale := fitScale(name, width, 8); scale >= 5 || !strings.Contains(name, " ") {
+		drawCentred(img, name, cx, cy, scale, c)
+		return
+	}
+	// Break at the space closest to the middle.
+	words := strings.Fields(name)
+	best, bestDiff := 1, len(name)
+	for i := 1; i < len(words); i++ {
+		first := len(strings.Join(words[:i], " "))
+		if diff := abs(first - (len(name) - first)); diff < bestDiff {
+			best, bestDiff = i, diff
+		}
+	}
+	line1, line2 := strings.Join(words[:best], " "), strings.Join(words[best:], " ")
+	scale := min(fitScale(line1, width, 8), fitScale(line2, width, 8))
+	gap := glyphHeight * scale
+	drawCentred(img, line1, cx, cy-gap, scale, c)
+	drawCentred(img, line2, cx, cy+gap, scale, c)
+}
+
+// drawScaled draws src into r with nearest-neighbour scaling, keeping its
+// aspect ratio.
+func drawScaled(dst draw.Image, r image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if sb.Empty() {
+		return
+	}
+	w, h := r.Dx(), r.Dy()
+	if sb.Dx()*h > sb.Dy()*w {
+		h = sb.Dy() * w / sb.Dx()
+	} else {
+		w = sb.Dx() * h / sb.Dy()
+	}
+	off := image.Pt(r.Min.X+(r.Dx()-w)/2, r.Min.Y+(r.Dy()-h)/2)
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			scaled.Set(x, y, src.At(sb.Min.X+x*sb.Dx()/w, sb.Min.Y+y*sb.Dy()/h))
+		}
+	}
+	draw.Draw(dst, scaled.Bounds().Add(off), scaled, image.Point{}, draw.Over)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// loadBadge reads <dir>/<code>.png if it exists.
+func loadBadge(dir, code string) image.Image {
+	if dir == "" || code == "" {
+		return nil
+	}
+	f, err := os.Open(filepath.Join(dir, code+".png"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// scoreCardMedia renders the score card for a finished match. Failures are
+// logged and the post goes out without an image.
+func (nb *NewsBot) scoreCardMedia(match *PremierLeagueMatch, topic *Topic) []Media {
+	if !nb.config.ScoreCards {
+		return nil
+	}
+	competition, code := topic.DisplayName, topic.League
+	if code == "" {
+		// Team topics span competitions; label the card with the match's.
+		competition, code = match.Competition.Name, match.Competition.Code
+	}
+	card := &ScoreCard{
+		Competition:     competition,
+		CompetitionCode: code,
+		Date:            match.UtcDate,
+		HomeTeam:        match.HomeTeam.Name,
+		AwayTeam:        match.AwayTeam.Name,
+		HomeScore:       match.Score.FullTime.Home,
+		AwayScore:       match.Score.FullTime.Away,
+		HomeColours:     nb.teamColours(match.HomeTeam.Name),
+		AwayColours:     nb.teamColours(match.AwayTeam.Name),
+		Badge:           loadBadge(nb.config.BadgeDir, code),
+	}
+	data, err := card.Render()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+	alt := fmt.Sprintf("%s full time: %s %d, %s %d", competition,
+		card.HomeTeam, card.HomeScore, card.AwayTeam, card.AwayScore)
+	return []Media{{Data: data, MimeType: "image/png", AltText: alt}}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ScorersResponse is the football-data.org /competitions/{code}/scorers
+// response.
+type ScorersResponse struct {
+	Competition struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	} `json:"competition"`
+	Season struct {
+		StartDate       string `json:"startDate"`
+		CurrentMatchday int    `json:"currentMatchday"`
+	} `json:"season"`
+	Scorers []Scorer `json:"scorers"`
+}
+
+// Scorer is one player's line in a competition's scoring chart.
+type Scorer struct {
+	Player struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Nationality string `json:"nationality,omitempty"`
+	} `json:"player"`
+	Team          MatchTeam `json:"team"`
+	PlayedMatches int       `json:"playedMatches"`
+	Goals         int       `json:"goals"`
+	Assists       *int      `json:"assists"`
+	Penalties     *int      `json:"penalties"`
+}
+
+// fetchScorers returns the competition's top scorers, highest first.
+func (nb *NewsBot) fetchScorers(ctx context.Context, league string, limit int) (*ScorersResponse, error) {
+	var resp ScorersResponse
+	if err := nb.footballData(ctx, fmt.Sprintf("/competitions/%s/scorers?limit=%d", league, limit), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Scorers) == 0 {
+		return nil, fmt.Errorf("no scorers listed for %s", league)
+	}
+	return &resp, nil
+}
+
+// scorerLine is the fact line for one player, e.g. "Haaland (Man City) 14
+// goals in 10 games, 2 penalties, 3 assists".
+func scorerLine(s Scorer) string {
+	line := fmt.Sprintf("%s (%s) %d goals in %d games", s.Player.Name, teamLabel(s.Team), s.Goals, s.PlayedMatches)
+	if s.Penalties != nil && *s.Penalties > 0 {
+		line += fmt.Sprintf(", %d penalties", *s.Penalties)
+	}
+	if s.Assists != nil && *s.Assists > 0 {
+		line += fmt.Sprintf(", %d assists", *s.Assists)
+	}
+	return line
+}
+
+// scorersKey identifies a state of the scoring charts so it is posted once.
+func scorersKey(prefix string, scorers []Scorer) string {
+	h := sha256.New()
+	for _, s := range scorers {
+		fmt.Fprintf(h, "%d:%d;", s.Player.ID, s.Goals)
+	}
+	return prefix + ":" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// generateScorersRace posts the golden-boot race for the topic's league.
+func (nb *NewsBot) generateScorersRace(ctx context.Context, topic *Topic) (*Draft, error) {
+	resp, err := nb.fetchScorers(ctx, topic.League, topic.TopN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch scorers: %w", err)
+	}
+	top := resp.Scorers[:min(topic.TopN, len(resp.Scorers))]
+	key := scorersKey("scorers:"+topic.League, top)
+	if nb.store.HasPosted(key) {
+		return nil, errNothingNew
+	}
+
+	var facts strings.Builder
+	fmt.Fprintf(&facts, "%s top scorers after matchday %d:\n", topic.DisplayName, resp.Season.CurrentMatchday)
+	for i, s := range top {
+		fmt.Fprintf(&facts, "%d. %s\n", i+1, scorerLine(s))
+	}
+	instructions := fmt.Sprintf(`Write an engaging tweet (%s) about the %s golden boot race.\n\n%s\nLead with the leader and how close the chasers are, using only the figures given. Include hashtags like %s. Output only the tweet text.`,
+		nb.lengthRequirement(topic), topic.DisplayName, facts.String(), topic.hashtags())
+	return nb.generateScorersDraft(ctx, topic, instructions, facts.String(), key, top[0], top, nil, resp)
+}
+
+// generateScorerMilestone posts when a player in the topic's league reaches
+// one of its goal milestones. The first run only records the current tallies
+// so existing totals don't all trigger at once; afterwards each player
+// crossing a milestone gets one post per run, for the highest milestone
+// crossed, until all are covered. A dry run records nothing.
+func (nb *NewsBot) generateScorerMilestone(ctx context.Context, topic *Topic) (*Draft, error) {
+	resp, err := nb.fetchScorers(ctx, topic.League, 50)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch scorers: %w", err)
+	}
+	prev, ok := nb.store.ScorerGoals(topic.League)
+	if !ok {
+		current := make(map[int]int, len(resp.Scorers))
+		for _, s := range resp.Scorers {
+			current[s.Player.ID] = s.Goals
+		}
+		if nb.config.DryRun {
+			log.Printf("Dry run: not recording the %s milestone baseline", topic.DisplayName)
+			return nil, errNothingNew
+		}
+		log.Printf("Recording %d %s scorer tallies as the milestone baseline", len(current), topic.DisplayName)
+		if err := nb.store.SetScorerGoals(topic.League, current); err != nil {
+			return nil, err
+		}
+		return nil, errNothingNew
+	}
+
+	season := resp.Season.StartDate[:min(4, len(resp.Season.StartDate))]
+	for _, s := range resp.Scorers {
+		// A brace or a missed run can cross several milestones at once; the
+		// highest is the news.
+		milestone := 0
+		for _, m := range topic.Milestones {
+			if prev[s.Player.ID] < m && s.Goals >= m && m > milestone {
+				milestone = m
+			}
+		}
+		if milestone == 0 {
+			continue
+		}
+		key := fmt.Sprintf("milestone:%s:%s:%d:%d", topic.League, season, s.Player.ID, milestone)
+		if nb.store.HasPosted(key) {
+			continue
+		}
+		facts := fmt.Sprintf("%s has reached %d %s goals this season: %s.",
+			s.Player.Name, milestone, topic.DisplayName, scorerLine(s))
+		for i, other := range resp.Scorers {
+			if other.Player.ID == s.Player.ID {
+				facts += fmt.Sprintf("\nThat puts them %s in the scoring chart.", ordinal(i+1))
+				break
+			}
+		}
+		instructions := fmt.Sprintf(`Write an engaging tweet (%s) celebrating this %s scoring milestone.\n\n%s\n\nUse only the figures given. Include hashtags like %s. Output only the tweet text.`,
+			nb.lengthRequirement(topic), topic.DisplayName, facts, topic.hashtags())
+		draft, err := nb.generateScorersDraft(ctx, topic, instructions, facts, key, s, []Scorer{s}, []int{milestone}, resp)
+		if err != nil {
+			return nil, err
+		}
+		player, goals := s.Player.ID, s.Goals
+		draft.onRecorded = func() error {
+			return nb.store.SetScorerGoals(topic.League, map[int]int{player: goals})
+		}
+		return draft, nil
+	}
+
+	// Nothing crossed a milestone; keep the tallies current.
+	current := make(map[int]int, len(resp.Scorers))
+	for _, s := range resp.Scorers {
+		if s.Goals != prev[s.Player.ID] {
+			current[s.Player.ID] = s.Goals
+		}
+	}
+	if len(current) > 0 && !nb.config.DryRun {
+		if err := nb.store.SetScorerGoals(topic.League, current); err != nil {
+			log.Printf("Warning: failed to update scorer tallies: %v", err)
+		}
+	}
+	return nil, errNothingNew
+}
+
+// generateScorersComparison compares the leading scorers across leagues.
+func (nb *NewsBot) generateScorersComparison(ctx context.Context, topic *Topic) (*Draft, error) {
+	var leaders []Scorer
+	var facts strings.Builder
+	facts.WriteString("Leading league scorers:\n")
+	for _, code := range topic.Leagues {
+		resp, err := nb.fetchScorers(ctx, code, 1)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			log.Printf("Skipping %s in the scorers comparison: %v", code, err)
+			continue
+		}
+		s := resp.Scorers[0]
+		leaders = append(leaders, s)
+		name := resp.Competition.Name
+		if name == "" {
+			name = code
+		}
+		fmt.Fprintf(&facts, "- %s: %s, %.2f goals per game\n", name, scorerLine(s), float64(s.Goals)/float64(max(s.PlayedMatches, 1)))
+	}
+	if len(leaders) < 2 {
+		return nil, fmt.Errorf("not enough leagues to compare scorers")
+	}
+	key := scorersKey("scorers-compare:"+strings.Join(topic.Leagues, ","), leaders)
+	if nb.store.HasPosted(key) {
+		return nil, errNothingNew
+	}
+
+	instructions := fmt.Sprintf(`Write an engaging tweet (%s) comparing the top scorers across Europe's big leagues.\n\n%s\nSay who leads overall and who has the best goals-per-game rate, using only the figures given. Include hashtags like %s. Output only the tweet text.`,
+		nb.lengthRequirement(topic), facts.String(), topic.hashtags())
+	return nb.generateScorersDraft(ctx, topic, instructions, facts.String(), key, topScorer(leaders), leaders, nil, nil)
+}
+
+// topScorer returns the scorer with the most goals, breaking ties by fewer
+// games played.
+func topScorer(scorers []Scorer) Scorer {
+	top := scorers[0]
+	for _, s := range scorers[1:] {
+		if s.Goals > top.Goals || s.Goals == top.Goals && s.PlayedMatches < top.PlayedMatches {
+			top = s
+		}
+	}
+	return top
+}
+
+// generateScorersDraft generates and verifies a scorers post about the
+// featured players, which must name lead; tallies lists any other goal
+// counts the post may quote. A topic prompt replaces the built-in
+// instructions, with the facts appended.
+func (nb *NewsBot) generateScorersDraft(ctx context.Context, topic *Topic, instructions, facts, key string, lead Scorer, featured []Scorer, tallies []int, source interface{}) (*Draft, error) {
+	prompt := instructions
+	if topic.Prompt != "" {
+		prompt = topic.Prompt + "\n\n" + facts
+	}
+	gen, err := nb.generateVerified(ctx, topic, prompt, GenerateOptions{
+		SystemPrompt: footballSystemPrompt(topic.hashtags(), nb.textLimit(topic)),
+		Temperature:  0.7,
+		MaxTokens:    200,
+	}, func(text string) error { return verifyScorersText(text, lead, featured, tallies) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s scorers post: %v", topic.DisplayName, err)
+	}
+	if source == nil {
+		source = featured
+	}
+	return &Draft{
+		Topic:     topic.Name,
+		Text:      gen.Text,
+		SourceKey: key,
+		Provider:  gen.Provider,
+		Model:     gen.Model,
+		Source:    source,
+	}, nil
+}
+
+var goalsRe = regexp.MustCompile(`(?i)\b(\d{1,3})\s?(?:league\s)?goals?\b`)
+
+// verifyScorersText checks that lead, the player the post is about, is named
+// and that every goal tally quoted belongs to one of the featured players or
+// is one of the extra tallies, such as the milestone reached.
+func verifyScorersText(text string, lead Scorer, featured []Scorer, extra []int) error {
+	textWords := make(map[string]bool)
+	for _, w := range foldWords(text) {
+		textWords[w] = true
+	}
+	named := false
+	for _, w := range foldWords(lead.Player.Name) {
+		if len(w) >= 3 && textWords[w] {
+			named = true
+			break
+		}
+	}
+	if !named {
+		return fmt.Errorf("it doesn't mention %s", lead.Player.Name)
+	}
+	tallies := make(map[int]bool)
+	for _, s := range featured {
+		tallies[s.Goals] = true
+	}
+	for _, n := range extra {
+		tallies[n] = true
+	}
+	for _, m := range goalsRe.FindAllStringSubmatch(text, -1) {
+		if n, _ := strconv.Atoi(m[1]); !tallies[n] {
+			return fmt.Errorf("it quotes %q but no featured player has %d goals", m[0], n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func testScorer(id int, name string, goals, games int) Scorer {
+	var s Scorer
+	s.Player.ID, s.Player.Name = id, name
+	s.Goals, s.PlayedMatches = goals, games
+	return s
+}
+
+func TestTopScorer(t *testing.T) {
+	kane := testScorer(1, "Harry Kane", 20, 18)
+	haaland := testScorer(2, "Erling Haaland", 21, 20)
+	mbappe := testScorer(3, "Kylian Mbappé", 21, 19)
+	if got := topScorer([]Scorer{kane, haaland, mbappe}); got.Player.ID != mbappe.Player.ID {
+		t.Errorf("topScorer = %s, want %s", got.Player.Name, mbappe.Player.Name)
+	}
+}
+
+func TestVerifyScorersText(t *testing.T) {
+	kane := testScorer(1, "Harry Kane", 20, 18)
+	haaland := testScorer(2, "Erling Haaland", 21, 20)
+	featured := []Scorer{kane, haaland}
+	tests := []struct {
+		name    string
+		text    string
+		lead    Scorer
+		extra   []int
+		wantErr bool
+	}{
+		{"names the lead", "Haaland leads Europe with 21 goals, Kane close behind on 20", haaland, nil, false},
+		{"misses the lead", "Kane has 20 goals in 18 games", haaland, nil, true},
+		{"lead isn't the first featured", "Haaland out in front on 21 goals", haaland, nil, false},
+		{"made-up tally", "Haaland has 22 goals", haaland, nil, true},
+		{"milestone tally", "Kane reaches 20 league goals, now on 20", kane, []int{20}, false},
+	}
+	for _, tt := range tests {
+		err := verifyScorersText(tt.text, tt.lead, featured, tt.extra)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StandingsResponse is the football-data.org /competitions/{code}/standings
+// response.
+type StandingsResponse struct {
+	Competition struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	} `json:"competition"`
+	Season struct {
+		CurrentMatchday int `json:"currentMatchday"`
+	} `json:"season"`
+	Standings []struct {
+		Stage string     `json:"stage"`
+		Type  string     `json:"type"` // TOTAL, HOME or AWAY
+		Group string     `json:"group"`
+		Table []TableRow `json:"table"`
+	} `json:"standings"`
+}
+
+// TableRow is one team's line in a league table.
+type TableRow struct {
+	Position       int       `json:"position"`
+	Team           MatchTeam `json:"team"`
+	PlayedGames    int       `json:"playedGames"`
+	Won            int       `json:"won"`
+	Draw           int       `json:"draw"`
+	Lost           int       `json:"lost"`
+	Points         int       `json:"points"`
+	GoalsFor       int       `json:"goalsFor"`
+	GoalsAgainst   int       `json:"goalsAgainst"`
+	GoalDifference int       `json:"goalDifference"`
+}
+
+// LeagueTable is the overall table of a league competition.
+type LeagueTable struct {
+	Code     string
+	Name     string
+	Matchday int
+	Rows     []TableRow
+}
+
+// fetchStandings returns the overall league table for the competition.
+func (nb *NewsBot) fetchStandings(ctx context.Context, league string) (*LeagueTable, error) {
+	return fromSources(nb, ctx, league, func(s FootballDataSource, c *Competition) (*LeagueTable, error) {
+		return s.Standings(ctx, c)
+	})
+}
+
+// key identifies this state of the table, so an unchanged table isn't
+// posted twice. Teams are identified by name so the key doesn't depend on
+// which provider served the table.
+func (t *LeagueTable) key() string {
+	h := sha256.New()
+	for _, r := range t.Rows {
+		fmt.Fprintf(h, "%s:%d:%d:%d;", teamSlug(r.Team.Name), r.Position, r.Points, r.PlayedGames)
+	}
+	return fmt.Sprintf("standings:%s:%s", t.Code, hex.EncodeToString(h.Sum(nil))[:12])
+}
+
+func (t *LeagueTable) snapshot() StandingsSnapshot {
+	snap := StandingsSnapshot{Matchday: t.Matchday, Positions: make(map[string]int, len(t.Rows))}
+	for _, r := range t.Rows {
+		snap.Positions[teamSlug(r.Team.Name)] = r.Position
+	}
+	return snap
+}
+
+// tableMovements describes notable changes against the previous table:
+// a new leader, teams entering or leaving the top and relegation zones, and
+// moves of three or more places.
+func tableMovements(rows []TableRow, prev StandingsSnapshot, topPlaces, relegation int) []string {
+	safe := len(rows) - relegation // lowest position outside the drop zone
+	var moves []string
+	for _, r := range rows {
+		was, ok := prev.Positions[teamSlug(r.Team.Name)]
+		if !ok || was == r.Position {
+			continue
+		}
+		name, now := teamLabel(r.Team), r.Position
+		switch {
+		case now == 1:
+			moves = append(moves, fmt.Sprintf("%s moved top (from %s)", name, ordinal(was)))
+		case now <= topPlaces && was > topPlaces:
+			moves = append(moves, fmt.Sprintf("%s moved into the top %s (%s)", name, numberWord(topPlaces), ordinal(now)))
+		case now > topPlaces && was <= topPlaces:
+			moves = append(moves, fmt.Sprintf("%s dropped out of the top %s (%s)", name, numberWord(topPlaces), ordinal(now)))
+		case now > safe && was <= safe:
+			moves = append(moves, fmt.Sprintf("%s dropped into the relegation zone (%s)", name, ordinal(now)))
+		case now <= safe && was > safe:
+			moves = append(moves, fmt.Sprintf("%s climbed out of the relegation zone (%s)", name, ordinal(now)))
+		case was-now >= 3:
+			moves = append(moves, fmt.Sprintf("%s up %d places to %s", name, was-now, ordinal(now)))
+		case now-was >= 3:
+			moves = append(moves, fmt.Sprintf("%s down %d places to %s", name, now-was, ordinal(now)))
+		}
+	}
+	return moves
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// goalDifference formats a goal difference as it appears in tables: "+3",
+// "-2" or "0".
+func goalDifference(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+func numberWord(n int) string {
+	words := []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+	if n >= 0 && n < len(words) {
+		return words[n]
+	}
+	return strconv.Itoa(n)
+}
+
+// generateStandings posts the top of the table with notable movements since
+// the last standings post for the league.
+func (nb *NewsBot) generateStandings(ctx context.Context, topic *Topic) (*Draft, error) {
+	table, err := nb.fetchStandings(ctx, topic.League)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch standings: %w", err)
+	}
+	key := table.key()
+	if nb.store.HasPosted(key) {
+		return nil, errNothingNew
+	}
+
+	var facts strings.Builder
+	fmt.Fprintf(&facts, "%s table after matchday %d (top %d):\n", topic.DisplayName, table.Matchday, topic.TopN)
+	for _, r := range table.Rows[:min(topic.TopN, len(table.Rows))] {
+		fmt.Fprintf(&facts, "%d. %s %d pts (played %d, GD %s)\n", r.Position, teamLabel(r.Team), r.Points, r.PlayedGames, goalDifference(r.GoalDifference))
+	}
+	if prev, ok := nb.store.StandingsSnapshot(table.Code); ok {
+		if moves := tableMovements(table.Rows, prev, topic.TopPlaces, topic.Relegation); len(moves) > 0 {
+			fmt.Fprintf(&facts, "\nChanges since matchday %d:\n- %s\n", prev.Matchday, strings.Join(moves, "\n- "))
+		}
+	}
+	if topic.Relegation > 0 && topic.Relegation < len(table.Rows) {
+		zone := table.Rows[len(table.Rows)-topic.Relegation:]
+		var names []string
+		for _, r := range zone {
+			names = append(names, fmt.Sprintf("%s (%d pts)", teamLabel(r.Team), r.Points))
+		}
+		safe := table.Rows[len(table.Rows)-topic.Relegation-1]
+		fmt.Fprintf(&facts, "\nRelegation zone: %s. %s are %s on %d pts.\n",
+			strings.Join(names, ", "), teamLabel(safe.Team), ordinal(safe.Position), safe.Points)
+	}
+
+	var prompt string
+	if topic.Prompt != "" {
+		prompt = topic.Prompt + "\n\n" + facts.String()
+	} else {
+		prompt = fmt.Sprintf(`Write an engaging tweet (%s) about the latest %s table.\n\n%s\nLead with the most newsworthy change, mention the leader, and use only the positions and points given. Include hashtags like %s. Output only the tweet text.`,
+			nb.lengthRequirement(topic), topic.DisplayName, facts.String(), topic.hashtags())
+	}
+	gen, err := nb.generateVerified(ctx, topic, prompt, GenerateOptions{
+		SystemPrompt: footballSystemPrompt(topic.hashtags(), nb.textLimit(topic)),
+		Temperature:  0.7,
+		MaxTokens:    200,
+	}, func(text string) error { return verifyStandingsText(text, table) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s standings: %v", topic.DisplayName, err)
+	}
+
+	draft := &Draft{
+		Topic:     topic.Name,
+		Text:      gen.Text,
+		SourceKey: key,
+		Provider:  gen.Provider,
+		Model:     gen.Model,
+		Source:    table,
+		onRecorded: func() error {
+			return nb.store.SetStandingsSnapshot(table.Code, table.snapshot())
+		},
+	}
+	if topic.Image {
+		data, err := renderTable(table, topic)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		} else {
+			alt := fmt.Sprintf("%s table after matchday %d", topic.DisplayName, table.Matchday)
+			draft.Media = []Media{{Data: data, MimeType: "image/png", AltText: alt}}
+		}
+	}
+	return draft, nil
+}
+
+var pointsRe = regexp.MustCompile(`(?i)\b(\d{1,3})\s?(?:pts|points)\b`)
+
+// verifyStandingsText checks that a standings post names the leader and
+// that every points total it quotes belongs to some team in the table.
+func verifyStandingsText(text string, table *LeagueTable) error {
+	textWords := make(map[string]bool)
+	for _, w := range foldWords(text) {
+		textWords[w] = true
+	}
+	if leader := table.Rows[0].Team; !mentionsTeam(textWords, leader) {
+		return fmt.Errorf("it doesn't mention the leaders, %s", leader.Name)
+	}
+	points := make(map[int]bool)
+	for _, r := range table.Rows {
+		points[r.Points] = true
+	}
+	for _, m := range pointsRe.FindAllStringSubmatch(text, -1) {
+		if n, _ := strconv.Atoi(m[1]); !points[n] {
+			return fmt.Errorf("it quotes %s but no team has %d points", m[0], n)
+		}
+	}
+	return nil
+}
+
+var (
+	tableTopZone    = color.RGBA{0x1f, 0x9d, 0x55, 0xff}
+	tableDropZone   = color.RGBA{0xc5, 0x30, 0x30, 0xff}
+	tableStripe     = color.RGBA{0x1a, 0x20, 0x2c, 0xff}
+	tableHeaderLine = color.RGBA{0x4a, 0x55, 0x68, 0xff}
+)
+
+// renderTable draws the full league table as a PNG, marking the top and
+// relegation zones.
+func renderTable(table *LeagueTable, topic *Topic) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, cardHeight))
+	fill(img, img.Bounds(), cardBackground)
+
+	title := fmt.Sprintf("%s - matchday %d", topic.DisplayName, table.Matchday)
+	drawText(img, title, 40, 28, fitScale(title, cardWidth-80, 4), cardForeground)
+
+	top := 90
+	rowHeight := (cardHeight - top - 20) / (len(table.Rows) + 1)
+	scale := max(1, min(3, (rowHeight-6)/glyphHeight))
+	columns := []struct {
+		label string
+		x     int
+	}{{"P", 760}, {"W", 840}, {"D", 900}, {"L", 960}, {"GD", 1030}, {"PTS", 1110}}
+
+	textY := func(row int) int { return top + row*rowHeight + (rowHeight-glyphHeight*scale)/2 }
+	drawText(img, "TEAM", 110, textY(0), scale, cardMuted)
+	for _, c := range columns {
+		drawText(img, c.label, c.x, textY(0), scale, cardMuted)
+	}
+	fill(img, image.Rect(40, top+rowHeight-2, cardWidth-40, top+rowHeight), tableHeaderLine)
+
+	safe := len(table.Rows) - topic.Relegation
+	for i, r := range table.Rows {
+		row := i + 1
+		y := top + row*rowHeight
+		if i%2 == 1 {
+			fill(img, image.Rect(40, y, cardWidth-40, y+rowHeight), tableStripe)
+		}
+		switch {
+		case r.Position <= topic.TopPlaces:
+			fill(img, image.Rect(40, y+2, 46, y+rowHeight-2), tableTopZone)
+		case r.Position > safe:
+			fill(img, image.Rect(40, y+2, 46, y+rowHeight-2), tableDropZone)
+		}
+		drawText(img, strconv.Itoa(r.Position), 60, textY(row), scale, cardMuted)
+		name := []rune(teamLabel(r.Team))
+		for len(name) > 0 && textWidth(string(name), scale) > columns[0].x-130 {
+			name = name[:len(name)-1]
+		}
+		drawText(img, string(name), 110, textY(row), scale, cardForeground)
+		values := []string{
+			strconv.Itoa(r.PlayedGames), strconv.Itoa(r.Won), strconv.Itoa(r.Draw), strconv.Itoa(r.Lost),
+			goalDifference(r.GoalDifference), strconv.Itoa(r.Points),
+		}
+		for j, v := range values {
+			drawText(img, v, columns[j].x, textY(row), scale, cardForeground)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode table image: %v", err)
+	}
+	return buf.Bytes(), nil
+}
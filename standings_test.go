@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testTable is a 20-team table with "Team n" in position n.
+func testTable() []TableRow {
+	rows := make([]TableRow, 20)
+	for i := range rows {
+		n := i + 1
+		rows[i] = TableRow{Position: n, Team: MatchTeam{ID: n, Name: fmt.Sprintf("Team %c FC", 'A'+i), ShortName: fmt.Sprintf("Team %d", n)}}
+	}
+	return rows
+}
+
+func TestTableMovements(t *testing.T) {
+	rows := testTable()
+	tests := []struct {
+		name string
+		was  map[int]int // position n -> previous position of the team now there
+		want []string
+	}{
+		{"first snapshot", nil, nil},
+		{"unchanged", map[int]int{1: 1, 10: 10}, nil},
+		{"small move", map[int]int{10: 11, 11: 10}, nil},
+		{"new leader", map[int]int{1: 3}, []string{"Team 1 moved top (from 3rd)"}},
+		{"into the top four", map[int]int{4: 6}, []string{"Team 4 moved into the top four (4th)"}},
+		{"out of the top four", map[int]int{5: 2}, []string{"Team 5 dropped out of the top four (5th)"}},
+		{"into the drop zone", map[int]int{18: 16}, []string{"Team 18 dropped into the relegation zone (18th)"}},
+		{"out of the drop zone", map[int]int{17: 19}, []string{"Team 17 climbed out of the relegation zone (17th)"}},
+		{"big climb", map[int]int{8: 11}, []string{"Team 8 up 3 places to 8th"}},
+		{"big fall", map[int]int{12: 9}, []string{"Team 12 down 3 places to 12th"}},
+		{"several", map[int]int{1: 2, 2: 1, 13: 16}, []string{"Team 1 moved top (from 2nd)", "Team 13 up 3 places to 13th"}},
+	}
+	for _, tt := range tests {
+		prev := StandingsSnapshot{Positions: make(map[string]int)}
+		for pos, was := range tt.was {
+			prev.Positions[teamSlug(rows[pos-1].Team.Name)] = was
+		}
+		got := tableMovements(rows, prev, 4, 3)
+		if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTableMovementsAcrossProviders(t *testing.T) {
+	rows := testTable()
+	rows[0].Team = MatchTeam{ID: 133604, Name: "Arsenal"}
+	prev := StandingsSnapshot{Positions: map[string]int{teamSlug("Arsenal FC"): 2}}
+	want := "Arsenal moved top (from 2nd)"
+	if got := strings.Join(tableMovements(rows, prev, 4, 3), "; "); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 111: "111th"} {
+		if got := ordinal(n); got != want {
+			t.Errorf("ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errNothingNew is returned by topic generators when every candidate item
+// has already been posted.
+var errNothingNew = errors.New("nothing new to post")
+
+// maxHistory bounds the number of post records kept in the state file.
+const maxHistory = 5000
+
+// maxHeadlines bounds the number of posted headlines kept for near-duplicate
+// checks.
+const maxHeadlines = 200
+
+// PostRecord is one published item in the post history.
+type PostRecord struct {
+	Key      string            `json:"key"` // e.g. "match:2025-03-01:wolverhampton-wanderers:liverpool" or "article:https://..."
+	Topic    string            `json:"topic"`
+	Text     string            `json:"text"`
+	PostIDs  map[string]string `json:"post_ids"`         // publisher name -> returned post ID (first post of a thread)
+	Errors   map[string]string `json:"errors,omitempty"` // publisher name -> failure, including partial threads
+	PostedAt time.Time         `json:"posted_at"`
+}
+
+type storeData struct {
+	Posts     []PostRecord                 `json:"posts"`
+	Live      map[int]LiveMatchState       `json:"live,omitempty"`      // match ID -> last state seen in live mode
+	Standings map[string]StandingsSnapshot `json:"standings,omitempty"` // competition code -> table as last posted
+	Scorers   map[string]map[int]int       `json:"scorers,omitempty"`   // competition code -> player ID -> goals when last checked for milestones
+	Headlines []HeadlineRecord             `json:"headlines,omitempty"` // headlines of posted articles, oldest first
+	Feeds     map[string]FeedState         `json:"feeds,omitempty"`     // feed URL -> last fetch, for conditional GET
+}
+
+// FeedState is an RSS or Atom feed as last fetched.
+type FeedState struct {
+	ETag         string           `json:"etag,omitempty"`
+	LastModified string           `json:"last_modified,omitempty"`
+	Articles     []NewsAPIArticle `json:"articles"`
+	Backlog      []string         `json:"backlog,omitempty"` // URLs of undated items already there on the first fetch, never posted
+	FetchedAt    time.Time        `json:"fetched_at"`
+}
+
+// HeadlineRecord is the headline of a posted article.
+type HeadlineRecord struct {
+	Title    string    `json:"title"`
+	PostedAt time.Time `json:"posted_at"`
+}
+
+// StandingsSnapshot is a league table as of the last standings post.
+type StandingsSnapshot struct {
+	Matchday  int            `json:"matchday"`
+	Positions map[string]int `json:"positions"` // teamSlug of the team name -> position
+	TakenAt   time.Time      `json:"taken_at"`
+}
+
+// LiveMatchState is the last polled state of an in-play match.
+type LiveMatchState struct {
+	Competition string    `json:"competition"`
+	Status      string    `json:"status"`
+	Home        int       `json:"home"`
+	Away        int       `json:"away"`
+	Goals       int       `json:"goals,omitempty"` // goals seen so far, including disallowed ones
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Store is a JSON-file backed history of what the bot has posted, used to
+// avoid posting the same match or article twice.
+type Store struct {
+	path   string
+	mu     sync.Mutex
+	data   storeData
+	posted map[string]bool
+}
+
+// OpenStore loads the state file at path, starting empty if it doesn't exist.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, posted: make(map[string]bool)}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %v", path, err)
+	}
+	for _, rec := range s.data.Posts {
+		if rec.Key != "" {
+			s.posted[rec.Key] = true
+		}
+	}
+	return s, nil
+}
+
+// HasPosted reports whether an item with the given key was already posted.
+func (s *Store) HasPosted(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.posted[key]
+}
+
+// Record appends a post to the history and persists it.
+func (s *Store) Record(rec PostRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec.PostedAt.IsZero() {
+		rec.PostedAt = time.Now().UTC()
+	}
+	s.data.Posts = append(s.data.Posts, rec)
+	if len(s.data.Posts) > maxHistory {
+		s.data.Posts = s.data.Posts[len(s.data.Posts)-maxHistory:]
+	}
+	if rec.Key != "" {
+		s.posted[rec.Key] = true
+	}
+	return s.save()
+}
+
+// LastPosted returns when the given topic was last posted.
+func (s *Store) LastPosted(topic string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.data.Posts) - 1; i >= 0; i-- {
+		if s.data.Posts[i].Topic == topic {
+			return s.data.Posts[i].PostedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// LiveStates returns a copy of the tracked live matches.
+func (s *Store) LiveStates() map[int]LiveMatchState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states := make(map[int]LiveMatchState, len(s.data.Live))
+	for id, st := range s.data.Live {
+		states[id] = st
+	}
+	return states
+}
+
+// SetLiveState stores the latest state of a live match, or stops tracking it
+// when state is nil, and persists the change.
+func (s *Store) SetLiveState(matchID int, state *LiveMatchState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == nil {
+		delete(s.data.Live, matchID)
+	} else {
+		if s.data.Live == nil {
+			s.data.Live = make(map[int]LiveMatchState)
+		}
+		state.UpdatedAt = time.Now().UTC()
+		s.data.Live[matchID] = *state
+	}
+	return s.save()
+}
+
+// StandingsSnapshot returns the table stored for the competition.
+func (s *Store) StandingsSnapshot(code string) (StandingsSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.data.Standings[code]
+	return snap, ok
+}
+
+// SetStandingsSnapshot replaces the stored table for the competition.
+func (s *Store) SetStandingsSnapshot(code string, snap StandingsSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Standings == nil {
+		s.data.Standings = make(map[string]StandingsSnapshot)
+	}
+	snap.TakenAt = time.Now().UTC()
+	s.data.Standings[code] = snap
+	return s.save()
+}
+
+// ScorerGoals returns the goal counts last stored for the competition's
+// scorers.
+func (s *Store) ScorerGoals(code string) (map[int]int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.data.Scorers[code]
+	goals := make(map[int]int, len(stored))
+	for id, n := range stored {
+		goals[id] = n
+	}
+	return goals, ok
+}
+
+// SetScorerGoals updates the stored goal counts of the given players.
+func (s *Store) SetScorerGoals(code string, goals map[int]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Scorers == nil {
+		s.data.Scorers = make(map[string]map[int]int)
+	}
+	if s.data.Scorers[code] == nil {
+		s.data.Scorers[code] = make(map[int]int)
+	}
+	for id, n := range goals {
+		s.data.Scorers[code][id] = n
+	}
+	return s.save()
+}
+
+// RecentHeadlines returns the headlines of articles posted within the last
+// window.
+func (s *Store) RecentHeadlines(window time.Duration) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var titles []string
+	for _, h := range s.data.Headlines {
+		if time.Since(h.PostedAt) <= window {
+			titles = append(titles, h.Title)
+		}
+	}
+	return titles
+}
+
+// AddHeadline records the headline of a posted article.
+func (s *Store) AddHeadline(title string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Headlines = append(s.data.Headlines, HeadlineRecord{Title: title, PostedAt: time.Now().UTC()})
+	if len(s.data.Headlines) > maxHeadlines {
+		s.data.Headlines = s.data.Headlines[len(s.data.Headlines)-maxHeadlines:]
+	}
+	return s.save()
+}
+
+// FeedState returns the stored state of the feed.
+func (s *Store) FeedState(url string) (FeedState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.data.Feeds[url]
+	return state, ok
+}
+
+// SetFeedState replaces the stored state of the feed.
+func (s *Store) SetFeedState(url string, state FeedState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Feeds == nil {
+		s.data.Feeds = make(map[string]FeedState)
+	}
+	state.FetchedAt = time.Now().UTC()
+	s.data.Feeds[url] = state
+	return s.save()
+}
+
+// save writes the state atomically via a temp file and rename. Callers must
+// hold s.mu.
+func (s *Store) save() error {
+	raw, err := json.MarshalIndent(&s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write state: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %v", err)
+	}
+	return nil
+}
+
+// matchKey and previewKey identify a match by its kick-off date and teams,
+// e.g. "match:2025-03-01:wolverhampton-wanderers:liverpool", so a match is
+// posted once whichever provider it comes from. Two clubs meet at most once
+// a day, so the competition isn't needed.
+func matchKey(match *PremierLeagueMatch) string {
+	return fixtureKey("match", match)
+}
+
+func previewKey(match *PremierLeagueMatch) string {
+	return fixtureKey("preview", match)
+}
+
+func fixtureKey(prefix string, match *PremierLeagueMatch) string {
+	return fmt.Sprintf("%s:%s:%s:%s", prefix, match.UtcDate.UTC().Format("2006-01-02"),
+		teamSlug(match.HomeTeam.Name), teamSlug(match.AwayTeam.Name))
+}
+
+// clubForms are the legal forms and filler words providers add to or leave
+// out of club names, e.g. "FC" in "Arsenal FC".
+var clubForms = map[string]bool{
+	"fc": true, "afc": true, "cf": true, "sc": true, "ac": true, "as": true, "ssc": true,
+	"sv": true, "vfb": true, "vfl": true, "tsg": true, "rc": true, "rcd": true,
+	"ogc": true, "cd": true, "ud": true, "bk": true, "and": true, "the": true,
+}
+
+// teamSlug normalises a team name so that providers' spellings of it agree,
+// e.g. "Brighton & Hove Albion FC" and "Brighton and Hove Albion" are both
+// "brighton-hove-albion". Legal forms and founding years are dropped.
+func teamSlug(name string) string {
+	var words []string
+	for _, w := range foldWords(name) {
+		if !clubForms[w] && strings.Trim(w, "0123456789") != "" {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		words = foldWords(name)
+	}
+	return strings.Join(words, "-")
+}
+
+// liveKey identifies one live alert for a match, e.g. "goal:3:2-1" or "ft".
+func liveKey(matchID int, event string) string {
+	return fmt.Sprintf("live:%d:%s", matchID, event)
+}
+
+func articleKey(article *NewsAPIArticle) string {
+	return "article:" + article.Url
+}
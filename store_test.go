@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("opening a missing state file: %v", err)
+	}
+	if s.HasPosted("match:1") {
+		t.Error("new store has posts")
+	}
+	postedAt := time.Date(2025, time.March, 1, 17, 0, 0, 0, time.UTC)
+	records := []PostRecord{
+		{Key: "match:1", Topic: "premier_league", Text: "Full time", PostIDs: map[string]string{"x": "185", "mastodon": "113"}, PostedAt: postedAt},
+		{Key: "article:https://example.com/a", Topic: "crypto", Text: "Bitcoin"},
+		{Topic: "quote", Text: "No key"},
+	}
+	for _, rec := range records {
+		if err := s.Record(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err = OpenStore(path)
+	if err != nil {
+		t.Fatalf("reopening: %v", err)
+	}
+	for _, key := range []string{"match:1", "article:https://example.com/a"} {
+		if !s.HasPosted(key) {
+			t.Errorf("%s not posted after reopening", key)
+		}
+	}
+	if s.HasPosted("match:2") || s.HasPosted("") {
+		t.Error("unposted key reported as posted")
+	}
+	if len(s.data.Posts) != 3 {
+		t.Fatalf("got %d records, want 3", len(s.data.Posts))
+	}
+	first := s.data.Posts[0]
+	if first.PostIDs["x"] != "185" || first.PostIDs["mastodon"] != "113" || !first.PostedAt.Equal(postedAt) {
+		t.Errorf("first record %+v", first)
+	}
+	if s.data.Posts[1].PostedAt.IsZero() {
+		t.Error("record without a time wasn't stamped")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".state-*")); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestStoreTrimsHistory(t *testing.T) {
+	s := &Store{path: filepath.Join(t.TempDir(), "state.json"), posted: make(map[string]bool)}
+	for i := 0; i < maxHistory; i++ {
+		s.data.Posts = append(s.data.Posts, PostRecord{Key: fmt.Sprintf("match:%d", i)})
+	}
+	if err := s.Record(PostRecord{Key: "match:new"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.data.Posts) != maxHistory {
+		t.Errorf("kept %d records, want %d", len(s.data.Posts), maxHistory)
+	}
+	if s.data.Posts[0].Key != "match:1" || s.data.Posts[maxHistory-1].Key != "match:new" {
+		t.Errorf("kept %s to %s", s.data.Posts[0].Key, s.data.Posts[maxHistory-1].Key)
+	}
+}
+
+func TestOpenStoreCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenStore(path); err == nil {
+		t.Error("opened a corrupt state file")
+	}
+}
+
+func TestTeamSlug(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"Arsenal FC", "arsenal"},
+		{"Arsenal", "arsenal"},
+		{"Brighton & Hove Albion FC", "brighton-hove-albion"},
+		{"Brighton and Hove Albion", "brighton-hove-albion"},
+		{"AFC Bournemouth", "bournemouth"},
+		{"TSG 1899 Hoffenheim", "hoffenheim"},
+		{"Manchester United FC", "manchester-united"},
+		{"FC", "fc"},
+	}
+	for _, tt := range tests {
+		if got := teamSlug(tt.name); got != tt.want {
+			t.Errorf("teamSlug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchKeyAcrossProviders(t *testing.T) {
+	kickoff := time.Date(2025, time.March, 1, 15, 0, 0, 0, time.UTC)
+	footballData := &PremierLeagueMatch{ID: 497512, UtcDate: kickoff,
+		HomeTeam: MatchTeam{Name: "Wolverhampton Wanderers FC"}, AwayTeam: MatchTeam{Name: "Liverpool FC"}}
+	sportsDB := &PremierLeagueMatch{ID: 2070001, Provider: "thesportsdb", UtcDate: kickoff,
+		HomeTeam: MatchTeam{Name: "Wolverhampton Wanderers"}, AwayTeam: MatchTeam{Name: "Liverpool"}}
+	if a, b := matchKey(footballData), matchKey(sportsDB); a != b {
+		t.Errorf("keys differ across providers: %q and %q", a, b)
+	}
+	if got, want := matchKey(footballData), "match:2025-03-01:wolverhampton-wanderers:liverpool"; got != want {
+		t.Errorf("matchKey = %q, want %q", got, want)
+	}
+	if matchKey(footballData) == previewKey(footballData) {
+		t.Error("result and preview share a key")
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// teamResultWindow is how far back team topics look for results to post, so
+// switching a club feed on doesn't post about matches long since played.
+const teamResultWindow = 72 * time.Hour
+
+// teamMatch is a match involving one of a team topic's followed clubs.
+type teamMatch struct {
+	match *PremierLeagueMatch
+	team  MatchTeam
+}
+
+// fetchTeamMatches returns the team's matches from teamResultWindow ago up to
+// lead time ahead, across all competitions, in a single request.
+func (nb *NewsBot) fetchTeamMatches(ctx context.Context, teamID int, lead time.Duration) ([]teamMatch, error) {
+	now := time.Now().UTC()
+	var resp PremierLeagueMatchesResponse
+	path := fmt.Sprintf("/teams/%d/matches?dateFrom=%s&dateTo=%s", teamID,
+		now.Add(-teamResultWindow).Format("2006-01-02"), now.Add(lead).Format("2006-01-02"))
+	if err := nb.footballData(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	var out []teamMatch
+	for i := range resp.Matches {
+		m := &resp.Matches[i]
+		team := m.HomeTeam
+		if m.AwayTeam.ID == teamID {
+			team = m.AwayTeam
+		}
+		out = append(out, teamMatch{match: m, team: team})
+	}
+	return out, nil
+}
+
+// generateTeamNews posts about the followed clubs: the latest result not yet
+// posted, otherwise a preview of the next fixture kicking off within the
+// topic's lead time. Results and previews share their keys with league and
+// preview topics, so a match is never covered twice.
+func (nb *NewsBot) generateTeamNews(ctx context.Context, topic *Topic) (*Draft, error) {
+	now := time.Now().UTC()
+	lead := time.Duration(topic.LeadTime)
+	var result, fixture *teamMatch
+	fetched := 0
+	for _, id := range topic.Teams {
+		matches, err := nb.fetchTeamMatches(ctx, id, lead)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			log.Printf("Failed to fetch matches for team %d: %v", id, err)
+			continue
+		}
+		fetched++
+		for i := range matches {
+			tm := &matches[i]
+			switch tm.match.Status {
+			case "FINISHED":
+				kickoff := tm.match.UtcDate
+				if now.Sub(kickoff) > teamResultWindow || nb.store.HasPosted(matchKey(tm.match)) {
+					continue
+				}
+				if result == nil || kickoff.After(result.match.UtcDate) {
+					result = tm
+				}
+			case "SCHEDULED", "TIMED":
+				kickoff := tm.match.UtcDate
+				if kickoff.Before(now) || kickoff.Sub(now) > lead || nb.store.HasPosted(previewKey(tm.match)) {
+					continue
+				}
+				if fixture == nil || kickoff.Before(fixture.match.UtcDate) {
+					fixture = tm
+				}
+			}
+		}
+	}
+	if fetched == 0 {
+		return nil, fmt.Errorf("failed to fetch matches for any of teams %v", topic.Teams)
+	}
+
+	switch {
+	case result != nil:
+		return nb.writeMatchResult(ctx, topic, result.match, result.team.Name)
+	case fixture != nil:
+		return nb.writeMatchPreview(ctx, topic, fixture.match, fixture.team.Name)
+	}
+	return nil, errNothingNew
+}
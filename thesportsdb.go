@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// theSportsDB serves competitions from TheSportsDB's v1 JSON API, keyed by
+// the competition's thesportsdb_id. It covers leagues football-data.org's
+// free tier doesn't, such as the League of Ireland.
+type theSportsDB struct {
+	apiKey string
+	client *http.Client
+	rate   *rateLimiter
+}
+
+func newTheSportsDB(apiKey string) *theSportsDB {
+	return &theSportsDB{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 10 * time.Second},
+		rate:   newRateLimiter(30), // the free tier's limit
+	}
+}
+
+// sportsDBInt is a number in TheSportsDB's JSON, which sends most numbers as
+// strings and scores as null before kick-off.
+type sportsDBInt struct {
+	N     int
+	Valid bool
+}
+
+func (n *sportsDBInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" || s == "" {
+		*n = sportsDBInt{}
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", b)
+	}
+	*n = sportsDBInt{N: v, Valid: true}
+	return nil
+}
+
+type sportsDBEvent struct {
+	ID         sportsDBInt `json:"idEvent"`
+	League     string      `json:"strLeague"`
+	HomeTeam   string      `json:"strHomeTeam"`
+	AwayTeam   string      `json:"strAwayTeam"`
+	HomeTeamID sportsDBInt `json:"idHomeTeam"`
+	AwayTeamID sportsDBInt `json:"idAwayTeam"`
+	HomeScore  sportsDBInt `json:"intHomeScore"`
+	AwayScore  sportsDBInt `json:"intAwayScore"`
+	Timestamp  string      `json:"strTimestamp"`
+	Date       string      `json:"dateEvent"`
+	Time       string      `json:"strTime"`
+	Venue      string      `json:"strVenue"`
+	Status     string      `json:"strStatus"`
+}
+
+type sportsDBTableRow struct {
+	Rank           sportsDBInt `json:"intRank"`
+	TeamID         sportsDBInt `json:"idTeam"`
+	Team           string      `json:"strTeam"`
+	Played         sportsDBInt `json:"intPlayed"`
+	Win            sportsDBInt `json:"intWin"`
+	Draw           sportsDBInt `json:"intDraw"`
+	Loss           sportsDBInt `json:"intLoss"`
+	GoalsFor       sportsDBInt `json:"intGoalsFor"`
+	GoalsAgainst   sportsDBInt `json:"intGoalsAgainst"`
+	GoalDifference sportsDBInt `json:"intGoalDifference"`
+	Points         sportsDBInt `json:"intPoints"`
+}
+
+// get GETs a v1 API path such as "eventspastleague.php?id=4328" and decodes
+// the JSON response into out.
+func (s *theSportsDB) get(ctx context.Context, path string, out interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, "GET", "https://www.thesportsdb.com/api/v1/json/"+s.apiKey+"/"+path, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.rate.Wait(ctx); err != nil {
+		return err
+	}
+	resp, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("TheSportsDB API error (%d): %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// leagueID returns the competition's TheSportsDB league ID.
+func (s *theSportsDB) leagueID(c *Competition) (string, error) {
+	if c.SportsDBID == "" {
+		return "", fmt.Errorf("competition %s has no thesportsdb_id", c.Code)
+	}
+	return c.SportsDBID, nil
+}
+
+func (s *theSportsDB) Results(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error) {
+	id, err := s.leagueID(c)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Events []sportsDBEvent `json:"events"`
+	}
+	if err := s.get(ctx, "eventspastleague.php?id="+id, &resp); err != nil {
+		return nil, err
+	}
+	var results []PremierLeagueMatch
+	for _, m := range s.matches(resp.Events, c) {
+		if m.Status == "FINISHED" {
+			results = append(results, m)
+		}
+	}
+	return matchesBetween(results, from, to), nil
+}
+
+func (s *theSportsDB) Fixtures(ctx context.Context, c *Competition, from, to time.Time) ([]PremierLeagueMatch, error) {
+	id, err := s.leagueID(c)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Events []sportsDBEvent `json:"events"`
+	}
+	if err := s.get(ctx, "eventsnextleague.php?id="+id, &resp); err != nil {
+		return nil, err
+	}
+	var fixtures []PremierLeagueMatch
+	for _, m := range s.matches(resp.Events, c) {
+		if m.Status == "SCHEDULED" || m.Status == "TIMED" {
+			fixtures = append(fixtures, m)
+		}
+	}
+	return matchesBetween(fixtures, from, to), nil
+}
+
+func (s *theSportsDB) Standings(ctx context.Context, c *Competition) (*LeagueTable, error) {
+	id, err := s.leagueID(c)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Table []sportsDBTableRow `json:"table"`
+	}
+	if err := s.get(ctx, "lookuptable.php?l="+id, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Table) == 0 {
+		return nil, fmt.Errorf("no league table for %s", c.Code)
+	}
+	table := &LeagueTable{Code: c.Code, Name: c.Name}
+	for _, r := range resp.Table {
+		table.Rows = append(table.Rows, TableRow{
+			Position:       r.Rank.N,
+			Team:           MatchTeam{ID: r.TeamID.N, Name: r.Team},
+			PlayedGames:    r.Played.N,
+			Won:            r.Win.N,
+			Draw:           r.Draw.N,
+			Lost:           r.Loss.N,
+			Points:         r.Points.N,
+			GoalsFor:       r.GoalsFor.N,
+			GoalsAgainst:   r.GoalsAgainst.N,
+			GoalDifference: r.GoalDifference.N,
+		})
+		// TheSportsDB has no matchday; the most games played is close.
+		table.Matchday = max(table.Matchday, r.Played.N)
+	}
+	sort.Slice(table.Rows, func(i, j int) bool { return table.Rows[i].Position < table.Rows[j].Position })
+	return table, nil
+}
+
+func (s *theSportsDB) TeamResults(ctx context.Context, teamID, limit int) ([]PremierLeagueMatch, error) {
+	var resp struct {
+		Results []sportsDBEvent `json:"results"`
+	}
+	if err := s.get(ctx, fmt.Sprintf("eventslast.php?id=%d", teamID), &resp); err != nil {
+		return nil, err
+	}
+	return latestFinished(s.matches(resp.Results, nil), limit), nil
+}
+
+// matches converts events to the football-data.org match model, skipping
+// any without a usable kick-off time.
+func (s *theSportsDB) matches(events []sportsDBEvent, c *Competition) []PremierLeagueMatch {
+	var out []PremierLeagueMatch
+	for _, e := range events {
+		kickoff, ok := e.kickoff()
+		if !ok {
+			continue
+		}
+		m := PremierLeagueMatch{
+			ID:       e.ID.N,
+			Provider: "thesportsdb",
+			HomeTeam: MatchTeam{ID: e.HomeTeamID.N, Name: e.HomeTeam},
+			AwayTeam: MatchTeam{ID: e.AwayTeamID.N, Name: e.AwayTeam},
+			UtcDate:  kickoff,
+			Venue:    e.Venue,
+			Status:   e.status(kickoff),
+		}
+		m.Score.FullTime.Home, m.Score.FullTime.Away = e.HomeScore.N, e.AwayScore.N
+		m.Competition.Name = e.League
+		if c != nil {
+			m.Competition.Code, m.Competition.Name = c.Code, c.Name
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// kickoff parses the event's kick-off time, which TheSportsDB gives in UTC
+// with or without an offset.
+func (e *sportsDBEvent) kickoff() (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, e.Timestamp); err == nil {
+			return t.UTC(), true
+		}
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05", e.Date+" "+e.Time); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// status maps TheSportsDB's free-text status to football-data.org's.
+func (e *sportsDBEvent) status(kickoff time.Time) string {
+	switch strings.ToUpper(strings.TrimSpace(e.Status)) {
+	case "MATCH FINISHED", "FT", "AET", "PEN":
+		return "FINISHED"
+	case "1H", "2H", "ET", "LIVE", "IN PROGRESS":
+		return "IN_PLAY"
+	case "HT", "BT":
+		return "PAUSED"
+	case "POSTPONED", "PST", "MATCH POSTPONED":
+		return "POSTPONED"
+	case "CANCELLED", "CANC", "ABD", "MATCH CANCELLED", "ABANDONED":
+		return "CANCELLED"
+	case "NOT STARTED", "NS", "TBD", "":
+		if e.HomeScore.Valid && e.AwayScore.Valid && kickoff.Before(time.Now()) {
+			// Older events often have scores but no status.
+			return "FINISHED"
+		}
+		return "TIMED"
+	}
+	return e.Status
+}
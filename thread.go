@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// PartialThreadError reports a thread that stopped part way through. The
+// tweets in PostedIDs are live; the caller records them so the item is not
+// posted again.
+type PartialThreadError struct {
+	PostedIDs []string
+	Total     int
+	Err       error
+}
+
+func (e *PartialThreadError) Error() string {
+	return fmt.Sprintf("thread stopped after %d of %d posts: %v", len(e.PostedIDs), e.Total, e.Err)
+}
+
+func (e *PartialThreadError) Unwrap() error { return e.Err }
+
+var sentenceEndRe = regexp.MustCompile(`[.!?…]+["'”’)\]]*\s+|\n\s*\n`)
+
+// splitSentences breaks text after sentence-ending punctuation and at
+// paragraph breaks, keeping the punctuation with its sentence.
+func splitSentences(text string) []string {
+	var sentences []string
+	last := 0
+	for _, loc := range sentenceEndRe.FindAllStringIndex(text, -1) {
+		if s := strings.TrimSpace(text[last:loc[1]]); s != "" {
+			sentences = append(sentences, s)
+		}
+		last = loc[1]
+	}
+	if s := strings.TrimSpace(text[last:]); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// splitThread splits text into numbered posts ("… 1/3") that each fit within
+// limit weighted characters, breaking on sentence boundaries where possible
+// and on word boundaries for sentences that are too long on their own.
+// Text that already fits is returned unchanged as a single post. A thread
+// is at most maxPosts long, with the last post truncated to fit; a single
+// post is never numbered.
+func splitThread(text string, limit, maxPosts int) []string {
+	text = norm.NFC.String(strings.TrimSpace(text))
+	if tweetWeightedLength(text) <= limit {
+		return []string{text}
+	}
+	if maxPosts == 1 {
+		return []string{truncateTweet(text, limit)}
+	}
+
+	// The " i/N" suffix width depends on N; retry with a wider reservation
+	// if the first pass produces ten or more posts.
+	for reserve := len(" 9/9"); ; reserve += 2 {
+		segments := packSegments(splitSentences(text), limit-reserve)
+		if maxPosts > 0 && len(segments) > maxPosts {
+			rest := strings.Join(segments[maxPosts-1:], " ")
+			segments = append(segments[:maxPosts-1], truncateTweet(rest, limit-reserve))
+		}
+		if len(segments) == 1 {
+			return segments
+		}
+		if len(fmt.Sprintf(" %d/%d", len(segments), len(segments))) <= reserve {
+			for i := range segments {
+				segments[i] = fmt.Sprintf("%s %d/%d", segments[i], i+1, len(segments))
+			}
+			return segments
+		}
+	}
+}
+
+// packSegments greedily joins sentences into segments of at most budget
+// weighted characters.
+func packSegments(sentences []string, budget int) []string {
+	var segments []string
+	current := ""
+	flush := func() {
+		if current != "" {
+			segments = append(segments, current)
+			current = ""
+		}
+	}
+	for _, sentence := range sentences {
+		if tweetWeightedLength(sentence) > budget {
+			flush()
+			segments = append(segments, splitWords(sentence, budget)...)
+			continue
+		}
+		candidate := sentence
+		if current != "" {
+			candidate = current + " " + sentence
+		}
+		if tweetWeightedLength(candidate) > budget {
+			flush()
+			candidate = sentence
+		}
+		current = candidate
+	}
+	flush()
+	return segments
+}
+
+// splitWords breaks an overlong sentence on whitespace, falling back to
+// character boundaries for a single word that is itself too long.
+func splitWords(sentence string, budget int) []string {
+	var segments []string
+	current := ""
+	for _, word := range strings.Fields(sentence) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if tweetWeightedLength(candidate) <= budget {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			segments = append(segments, current)
+		}
+		current = word
+		for tweetWeightedLength(current) > budget {
+			head := headWithin(current, budget)
+			segments = append(segments, head)
+			current = current[len(head):]
+		}
+	}
+	if current != "" {
+		segments = append(segments, current)
+	}
+	return segments
+}
+
+// headWithin returns the longest prefix of s, cut on a token boundary, that
+// fits within budget weighted characters. It always returns at least one
+// character, cutting between characters if the leading token is wider than
+// the budget on its own, so callers splitting a long word make progress.
+func headWithin(s string, budget int) string {
+	used, end := 0, 0
+	for _, t := range tokenizeTweet(s) {
+		if used+t.weight > budget*tweetTextScale {
+			break
+		}
+		used += t.weight
+		end += len(t.text)
+	}
+	if end == 0 {
+		for _, t := range clusterTokens(s) {
+			if used+t.weight > budget*tweetTextScale {
+				break
+			}
+			used += t.weight
+			end += len(t.text)
+		}
+	}
+	if end == 0 {
+		end, _ = nextCluster(s) // budget is smaller than one character
+	}
+	return s[:end]
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSplitThread(t *testing.T) {
+	var sentences []string
+	for i := 0; len(strings.Join(sentences, " ")) < 1400; i++ {
+		sentences = append(sentences, fmt.Sprintf("Sentence number %d is short but not that short, so it fills a fair bit of a post on X.", i))
+	}
+	shortSentences := strings.Join(sentences, " ")
+
+	tests := []struct {
+		name     string
+		text     string
+		maxPosts int
+		wantMax  int // most posts expected
+	}{
+		{"fits in one post", "Just one tweet.", 5, 1},
+		{"capped at max posts", shortSentences, 5, 5},
+		{"capped at one post", shortSentences, 1, 1},
+		{"uncapped", shortSentences, 0, 20},
+		{"oversize word", strings.Repeat("x", 700), 5, 3},
+		{"long sentence", strings.Repeat("word ", 120), 5, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := splitThread(tt.text, maxTweetLength, tt.maxPosts)
+			if len(segments) == 0 || len(segments) > tt.wantMax {
+				t.Fatalf("got %d posts, want 1 to %d", len(segments), tt.wantMax)
+			}
+			for i, s := range segments {
+				if n := tweetWeightedLength(s); n > maxTweetLength {
+					t.Errorf("post %d is %d weighted characters: %q", i+1, n, s)
+				}
+				if len(segments) > 1 && !strings.HasSuffix(s, fmt.Sprintf(" %d/%d", i+1, len(segments))) {
+					t.Errorf("post %d isn't numbered: %q", i+1, s)
+				}
+				if len(segments) == 1 && strings.HasSuffix(s, " 1/1") {
+					t.Errorf("single post is numbered: %q", s)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitThreadCapTruncatesLastPost(t *testing.T) {
+	text := strings.Repeat("This sentence is here to pad the thread out. ", 60)
+	segments := splitThread(text, maxTweetLength, 3)
+	if len(segments) != 3 {
+		t.Fatalf("got %d posts, want 3", len(segments))
+	}
+	if !strings.HasSuffix(segments[2], "… 3/3") {
+		t.Errorf("last post isn't truncated: %q", segments[2])
+	}
+}
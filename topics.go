@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration that unmarshals from strings like "6h".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"6h\": %v", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Topic is one entry in the rotation: what to post about, how to write it and
+// when it is allowed to run.
+type Topic struct {
+	Name        string   `json:"name"`             // unique ID, e.g. "PL" or "crypto"
+	Kind        string   `json:"kind"`             // "league", "preview", "roundup", "standings", "scorers", "milestones", "scorers-compare", "team", "news" or "crypto"
+	League      string   `json:"league,omitempty"` // football-data.org competition code
+	DisplayName string   `json:"display_name"`
+	Hashtags    []string `json:"hashtags,omitempty"`
+	Generators  []string `json:"generators,omitempty"` // overrides GENERATOR_CHAIN
+	Prompt      string   `json:"prompt,omitempty"`     // replaces the built-in instructions
+	Weight      float64  `json:"weight"`
+	Days        []string `json:"days,omitempty"`              // e.g. ["sat", "sun"]; empty means every day
+	Hours       string   `json:"hours,omitempty"`             // e.g. "08:00-22:00"; may wrap past midnight
+	Timezone    string   `json:"timezone,omitempty"`          // for Days, Hours and Schedule, default UTC
+	Cooldown    Duration `json:"cooldown,omitempty"`          // minimum gap between posts for this topic
+	Schedule    string   `json:"schedule,omitempty"`          // cron expression for serve mode
+	Thread      bool     `json:"thread,omitempty"`            // allow long posts, split into a thread on X
+	LeadTime    Duration `json:"lead_time,omitempty"`         // preview fixtures kicking off within this long, default 3h (24h for team topics)
+	TopN        int      `json:"top_n,omitempty"`             // standings rows or scorers to post, default 5
+	TopPlaces   int      `json:"top_places,omitempty"`        // size of the top zone in standings posts, default 4
+	Relegation  int      `json:"relegation_places,omitempty"` // size of the relegation zone, default 3
+	Image       bool     `json:"image,omitempty"`             // attach a rendered image where the kind supports one
+	Milestones  []int    `json:"milestones,omitempty"`        // goal tallies that trigger milestone posts, default 10, 20, 30
+	Leagues     []string `json:"leagues,omitempty"`           // competitions compared by scorers-compare topics, default the big five
+	Teams       []int    `json:"teams,omitempty"`             // football-data.org team IDs followed by team topics, default TEAM_IDS
+	Query       string   `json:"query,omitempty"`             // NewsAPI search query for news topics, e.g. "Formula 1 OR F1"
+	Language    string   `json:"language,omitempty"`          // NewsAPI article language for news topics, e.g. "en"
+	Country     string   `json:"country,omitempty"`           // NewsAPI top-headlines country for news topics, e.g. "gb"
+	Domains     []string `json:"domains,omitempty"`           // sites news topics are limited to, e.g. ["bbc.co.uk"]
+	Feeds       []string `json:"feeds,omitempty"`             // RSS or Atom feed URLs news topics also read
+	Persona     string   `json:"persona,omitempty"`           // who news posts are written as, e.g. "an F1 journalist"
+
+	generator   Generator
+	competition *Competition
+	schedule    *CronSchedule
+	location    *time.Location
+	days        map[time.Weekday]bool
+	fromMin     int
+	toMin       int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// defaultTopics is the built-in rotation: the big leagues and crypto, or a
+// single club feed when TEAM_IDS is set.
+func defaultTopics(config *Config) []*Topic {
+	if len(config.TeamIDs) > 0 {
+		return []*Topic{{
+			Name:        "team",
+			Kind:        "team",
+			DisplayName: config.TeamName,
+			Hashtags:    config.TeamHashtags,
+			Teams:       config.TeamIDs,
+			Weight:      1,
+		}}
+	}
+	league := func(code string) *Topic {
+		return &Topic{Name: code, Kind: "league", League: code, Weight: 1}
+	}
+	return []*Topic{
+		league("PL"),
+		league("PD"),
+		league("BL1"),
+		league("SA"),
+		league("FL1"),
+		league("IRL"),
+		{
+			Name:        "crypto",
+			Kind:        "crypto",
+			DisplayName: "Crypto",
+			Hashtags:    []string{"#Crypto", "#Blockchain", "#News"},
+			Weight:      1,
+		},
+	}
+}
+
+// loadTopics reads the topic registry from TOPICS_FILE, falling back to the
+// built-in rotation when the file doesn't exist.
+func loadTopics(config *Config) ([]*Topic, error) {
+	path := config.TopicsFile
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return defaultTopics(config), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topics file: %v", err)
+	}
+	var topics []*Topic
+	if err := json.Unmarshal(raw, &topics); err != nil {
+		return nil, fmt.Errorf("failed to parse topics file %s: %v", path, err)
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("topics file %s defines no topics", path)
+	}
+	// A topic without a weight is in the rotation at weight 1; only an
+	// explicit 0 takes it out.
+	var fields []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse topics file %s: %v", path, err)
+	}
+	for i, t := range topics {
+		if _, ok := fields[i]["weight"]; !ok {
+			t.Weight = 1
+		}
+	}
+	return topics, nil
+}
+
+// validate checks the topic definition and precomputes its schedule window.
+func (t *Topic) validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("topic is missing a name")
+	}
+	switch t.Kind {
+	case "league", "preview", "roundup", "standings", "scorers", "milestones":
+		if t.League == "" {
+			return fmt.Errorf("topic %s: %s topics need a league code", t.Name, t.Kind)
+		}
+	case "scorers-compare":
+		if len(t.Leagues) == 0 {
+			t.Leagues = append(t.Leagues, bigFiveLeagues...)
+		}
+	case "team":
+		if len(t.Teams) == 0 {
+			return fmt.Errorf("topic %s: team topics need team IDs (set teams or TEAM_IDS)", t.Name)
+		}
+		for _, id := range t.Teams {
+			if id <= 0 {
+				return fmt.Errorf("topic %s: invalid team ID %d", t.Name, id)
+			}
+		}
+	case "news":
+		if t.Query == "" && len(t.Domains) == 0 && len(t.Feeds) == 0 {
+			return fmt.Errorf("topic %s: news topics need a query, domains or feeds", t.Name)
+		}
+	case "crypto":
+		// A news topic with crypto defaults.
+		if t.Query == "" {
+			t.Query = "crypto"
+		}
+		if t.Language == "" {
+			t.Language = "en"
+		}
+		if t.Persona == "" {
+			t.Persona = "an expert crypto Twitter writer"
+		}
+	default:
+		return fmt.Errorf("topic %s: unknown kind %q", t.Name, t.Kind)
+	}
+	if t.DisplayName == "" {
+		t.DisplayName = t.Name
+	}
+	if (t.Kind == "news" || t.Kind == "crypto") && t.Persona == "" {
+		t.Persona = fmt.Sprintf("an expert %s news Twitter writer", t.DisplayName)
+	}
+	for _, feed := range t.Feeds {
+		if u, err := url.Parse(feed); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("topic %s: invalid feed URL %q", t.Name, feed)
+		}
+	}
+	if t.Kind == "preview" && t.LeadTime == 0 {
+		t.LeadTime = Duration(3 * time.Hour)
+	}
+	if t.Kind == "team" && t.LeadTime == 0 {
+		t.LeadTime = Duration(24 * time.Hour)
+	}
+	if t.TopN == 0 {
+		t.TopN = 5
+	}
+	if t.Kind == "milestones" && len(t.Milestones) == 0 {
+		t.Milestones = []int{10, 20, 30}
+	}
+	if t.Kind == "standings" {
+		if t.TopPlaces == 0 {
+			t.TopPlaces = 4
+		}
+		if t.Relegation == 0 {
+			t.Relegation = 3
+		}
+		if t.TopPlaces < 0 || t.Relegation < 0 {
+			return fmt.Errorf("topic %s: top_places and relegation_places must be positive", t.Name)
+		}
+	}
+	if t.TopN < 0 {
+		return fmt.Errorf("topic %s: top_n must be positive", t.Name)
+	}
+	if t.Weight < 0 {
+		return fmt.Errorf("topic %s: weight must not be negative", t.Name)
+	}
+
+	t.location = time.UTC
+	if t.Timezone != "" {
+		loc, err := time.LoadLocation(t.Timezone)
+		if err != nil {
+			return fmt.Errorf("topic %s: %v", t.Name, err)
+		}
+		t.location = loc
+	}
+
+	t.days = nil
+	if len(t.Days) > 0 {
+		t.days = make(map[time.Weekday]bool)
+		for _, d := range t.Days {
+			wd, ok := weekdays[strings.ToLower(d[:min(3, len(d))])]
+			if !ok {
+				return fmt.Errorf("topic %s: unknown day %q", t.Name, d)
+			}
+			t.days[wd] = true
+		}
+	}
+
+	t.schedule = nil
+	if t.Schedule != "" {
+		sched, err := ParseCron(t.Schedule)
+		if err != nil {
+			return fmt.Errorf("topic %s: %v", t.Name, err)
+		}
+		t.schedule = sched
+	}
+
+	t.fromMin, t.toMin = 0, 0
+	if t.Hours != "" {
+		from, to, ok := strings.Cut(t.Hours, "-")
+		var err error
+		if ok {
+			if t.fromMin, err = parseClock(from); err == nil {
+				t.toMin, err = parseClock(to)
+			}
+		}
+		if !ok || err != nil {
+			return fmt.Errorf("topic %s: hours must look like \"08:00-22:00\"", t.Name)
+		}
+	}
+	return nil
+}
+
+// parseClock converts "HH:MM" to minutes past midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inWindow reports whether now falls within the topic's day and hour window.
+func (t *Topic) inWindow(now time.Time) bool {
+	local := now.In(t.location)
+	if t.days != nil && !t.days[local.Weekday()] {
+		return false
+	}
+	if t.Hours == "" || t.fromMin == t.toMin {
+		return true
+	}
+	m := local.Hour()*60 + local.Minute()
+	if t.fromMin < t.toMin {
+		return m >= t.fromMin && m < t.toMin
+	}
+	return m >= t.fromMin || m < t.toMin // window wraps past midnight
+}
+
+// hashtags renders the topic's hashtags for use in a prompt.
+func (t *Topic) hashtags() string {
+	return strings.Join(t.Hashtags, " ")
+}
+
+// topicAllowed reports whether the topic may post now: within its day and
+// hour window and past its cooldown.
+func (nb *NewsBot) topicAllowed(t *Topic, now time.Time) bool {
+	if !t.inWindow(now) {
+		return false
+	}
+	if t.Cooldown > 0 {
+		if last, ok := nb.store.LastPosted(t.Name); ok && now.Sub(last) < time.Duration(t.Cooldown) {
+			return false
+		}
+	}
+	return true
+}
+
+// eligibleTopics returns the topics allowed to run now, ordered by a
+// weighted random draw without replacement so callers can fall back to the
+// next one when a topic has nothing to post.
+func (nb *NewsBot) eligibleTopics(now time.Time, candidates []*Topic) []*Topic {
+	var pool []*Topic
+	for _, t := range candidates {
+		if t.Weight > 0 && nb.topicAllowed(t, now) {
+			pool = append(pool, t)
+		}
+	}
+
+	ordered := make([]*Topic, 0, len(pool))
+	for len(pool) > 0 {
+		total := 0.0
+		for _, t := range pool {
+			total += t.Weight
+		}
+		r := rand.Float64() * total
+		i := 0
+		for ; i < len(pool)-1; i++ {
+			r -= pool[i].Weight
+			if r < 0 {
+				break
+			}
+		}
+		ordered = append(ordered, pool[i])
+		pool = append(pool[:i], pool[i+1:]...)
+	}
+	return ordered
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidatePreviewTopic(t *testing.T) {
+	topic := &Topic{Name: "PL-preview", Kind: "preview", League: "PL"}
+	if err := topic.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := time.Duration(topic.LeadTime); got != 3*time.Hour {
+		t.Errorf("default lead time = %v, want 3h", got)
+	}
+	if err := (&Topic{Name: "next", Kind: "preview"}).validate(); err == nil {
+		t.Error("accepted a preview topic without a league")
+	}
+}
+
+func TestValidateTeamTopic(t *testing.T) {
+	topic := &Topic{Name: "spurs", Kind: "team", Teams: []int{73}}
+	if err := topic.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := time.Duration(topic.LeadTime); got != 24*time.Hour {
+		t.Errorf("default lead time = %v, want 24h", got)
+	}
+	for _, teams := range [][]int{nil, {0}} {
+		if err := (&Topic{Name: "club", Kind: "team", Teams: teams}).validate(); err == nil {
+			t.Errorf("accepted a team topic with teams %v", teams)
+		}
+	}
+}
+
+func TestDefaultTopicsFollowTeams(t *testing.T) {
+	topics := defaultTopics(&Config{TeamIDs: []int{73}, TeamName: "Spurs"})
+	if len(topics) != 1 {
+		t.Fatalf("got %d topics, want a single team topic", len(topics))
+	}
+	if tp := topics[0]; tp.Kind != "team" || tp.DisplayName != "Spurs" || len(tp.Teams) != 1 || tp.Teams[0] != 73 {
+		t.Errorf("topic = %+v", tp)
+	}
+}
+
+func TestValidateNewsTopics(t *testing.T) {
+	crypto := &Topic{Name: "crypto", Kind: "crypto"}
+	if err := crypto.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if crypto.Query != "crypto" || crypto.Language != "en" || crypto.Persona != "an expert crypto Twitter writer" {
+		t.Errorf("crypto defaults = %q, %q, %q", crypto.Query, crypto.Language, crypto.Persona)
+	}
+
+	f1 := &Topic{Name: "f1", Kind: "news", DisplayName: "F1", Query: "Formula 1 OR F1"}
+	if err := f1.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "an expert F1 news Twitter writer"; f1.Persona != want {
+		t.Errorf("persona = %q, want %q", f1.Persona, want)
+	}
+
+	if err := (&Topic{Name: "empty", Kind: "news"}).validate(); err == nil {
+		t.Error("accepted a news topic without a query or domains")
+	}
+}
+
+func TestTopicAllowed(t *testing.T) {
+	store, err := OpenStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb := &NewsBot{store: store}
+	topic := &Topic{Name: "PL", Kind: "league", League: "PL", Days: []string{"sat"}, Hours: "12:00-20:00", Cooldown: Duration(6 * time.Hour)}
+	if err := topic.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	saturday := time.Date(2025, time.March, 1, 15, 0, 0, 0, time.UTC)
+	if !nb.topicAllowed(topic, saturday) {
+		t.Error("not allowed inside its window")
+	}
+	if nb.topicAllowed(topic, saturday.Add(6*time.Hour)) {
+		t.Error("allowed outside its hours")
+	}
+	if nb.topicAllowed(topic, saturday.Add(24*time.Hour)) {
+		t.Error("allowed on the wrong day")
+	}
+
+	if err := store.Record(PostRecord{Key: "match:1", Topic: "PL", PostedAt: saturday.Add(-5 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if nb.topicAllowed(topic, saturday) {
+		t.Error("allowed during its cooldown")
+	}
+	if !nb.topicAllowed(topic, saturday.Add(90*time.Minute)) {
+		t.Error("not allowed once its cooldown has passed")
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Weighting rules from twitter-text v3 (config/v3.json). Lengths are kept in
+// scaled units: a "light" character costs 100, everything else 200, and the
+// result is divided by tweetTextScale.
+const (
+	tweetTextScale         = 100
+	tweetTextDefaultWeight = 200
+	tweetTextURLLength     = 23 // every link is wrapped in a t.co URL
+)
+
+var tweetTextLightRanges = [][2]rune{
+	{0x0000, 0x10FF}, // Latin, Greek, Cyrillic, Hebrew, Arabic, ...
+	{0x2000, 0x200D}, // spaces and joiners
+	{0x2010, 0x201F}, // dashes and quotes
+	{0x2032, 0x2037}, // primes
+}
+
+var tweetURLRe = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s]+`)
+
+// tweetToken is an indivisible piece of tweet text: a URL or a single
+// user-perceived character (grapheme cluster).
+type tweetToken struct {
+	text   string
+	weight int // scaled
+	space  bool
+	url    bool
+}
+
+// tokenizeTweet splits NFC-normalised text into URLs and grapheme clusters
+// carrying their twitter-text weights.
+func tokenizeTweet(text string) []tweetToken {
+	var tokens []tweetToken
+	last := 0
+	for _, loc := range tweetURLRe.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		// Trailing punctuation isn't part of the link.
+		end = start + len(strings.TrimRight(text[start:end], ".,!?;:'\")]}"))
+		tokens = append(tokens, clusterTokens(text[last:start])...)
+		tokens = append(tokens, tweetToken{text: text[start:end], weight: tweetTextURLLength * tweetTextScale, url: true})
+		last = end
+	}
+	return append(tokens, clusterTokens(text[last:])...)
+}
+
+func clusterTokens(s string) []tweetToken {
+	var tokens []tweetToken
+	for len(s) > 0 {
+		n, emoji := nextCluster(s)
+		cluster := s[:n]
+		r, _ := utf8.DecodeRuneInString(cluster)
+		t := tweetToken{text: cluster, space: unicode.IsSpace(r)}
+		if emoji {
+			t.weight = tweetTextDefaultWeight // an emoji sequence counts once
+		} else {
+			for _, cr := range cluster {
+				t.weight += runeWeight(cr)
+			}
+		}
+		tokens = append(tokens, t)
+		s = s[n:]
+	}
+	return tokens
+}
+
+func runeWeight(r rune) int {
+	for _, rng := range tweetTextLightRanges {
+		if r >= rng[0] && r <= rng[1] {
+			return tweetTextScale
+		}
+	}
+	return tweetTextDefaultWeight
+}
+
+// nextCluster returns the byte length of the grapheme cluster at the start of
+// s and whether it is an emoji sequence. It covers combining marks, variation
+// selectors, skin tones, ZWJ sequences, keycaps, tags and flag pairs, which is
+// enough for tweet text without pulling in a full UAX #29 implementation.
+func nextCluster(s string) (int, bool) {
+	r, n := utf8.DecodeRuneInString(s)
+	emoji := isEmojiBase(r)
+
+	if isRegionalIndicator(r) {
+		if r2, n2 := utf8.DecodeRuneInString(s[n:]); isRegionalIndicator(r2) {
+			return n + n2, true
+		}
+		return n, true
+	}
+
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case next == 0xFE0F: // emoji presentation selector
+			emoji = true
+			n += size
+		case next == 0x20E3: // combining enclosing keycap
+			emoji = true
+			n += size
+		case unicode.Is(unicode.Mn, next) || unicode.Is(unicode.Me, next) || next == 0xFE0E:
+			n += size
+		case next >= 0x1F3FB && next <= 0x1F3FF: // skin tone modifiers
+			n += size
+		case next >= 0xE0020 && next <= 0xE007F: // tag sequences (subdivision flags)
+			n += size
+		case next == 0x200D: // zero width joiner glues the following emoji on
+			after, afterSize := utf8.DecodeRuneInString(s[n+size:])
+			if n+size >= len(s) || !isEmojiBase(after) {
+				return n, emoji
+			}
+			emoji = true
+			n += size + afterSize
+		default:
+			return n, emoji
+		}
+	}
+	return n, emoji
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isEmojiBase(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF, // pictographs, emoticons, transport, symbols
+		r >= 0x2600 && r <= 0x27BF, // misc symbols and dingbats
+		r >= 0x2B00 && r <= 0x2BFF, // arrows and stars such as ⭐
+		r >= 0x2300 && r <= 0x23FF: // technical symbols such as ⌚ and ⏰
+		return true
+	}
+	return false
+}
+
+// tweetWeightedLength returns the length of text as X counts it: NFC
+// normalised, CJK and emoji counted as two, and every URL as 23.
+func tweetWeightedLength(text string) int {
+	total := 0
+	for _, t := range tokenizeTweet(norm.NFC.String(text)) {
+		total += t.weight
+	}
+	return total / tweetTextScale
+}
+
+var trailingHashtagsRe = regexp.MustCompile(`(?:\s+#[\pL\pN_]+)+\s*$`)
+
+// graphemeLength returns the length of text in user-perceived characters,
+// as Mastodon and Bluesky count it.
+func graphemeLength(text string) int {
+	total := 0
+	for _, t := range graphemeTokens(norm.NFC.String(text)) {
+		total += t.weight
+	}
+	return total / tweetTextScale
+}
+
+// graphemeTokens splits text like tokenizeTweet, but weighs every
+// character the same and a URL by its length.
+func graphemeTokens(text string) []tweetToken {
+	tokens := tokenizeTweet(text)
+	for i := range tokens {
+		if tokens[i].url {
+			tokens[i].weight = utf8.RuneCountInString(tokens[i].text) * tweetTextScale
+		} else {
+			tokens[i].weight = tweetTextScale
+		}
+	}
+	return tokens
+}
+
+// truncateTweet shortens text to fit within limit weighted characters. It
+// never splits a character or URL, prefers to cut at a word boundary, and
+// keeps a trailing block of hashtags intact when there is room for it.
+func truncateTweet(text string, limit int) string {
+	return truncateTokens(text, limit, tweetWeightedLength, tokenizeTweet)
+}
+
+// truncateGraphemes shortens text to at most limit user-perceived
+// characters, for networks that don't use X's weighting. It cuts the same
+// way as truncateTweet.
+func truncateGraphemes(text string, limit int) string {
+	return truncateTokens(text, limit, graphemeLength, graphemeTokens)
+}
+
+func truncateTokens(text string, limit int, length func(string) int, tokenize func(string) []tweetToken) string {
+	text = norm.NFC.String(strings.TrimSpace(text))
+	if length(text) <= limit {
+		return text
+	}
+
+	const ellipsis = "…"
+	body, tags := text, ""
+	if loc := trailingHashtagsRe.FindStringIndex(text); loc != nil && loc[0] > 0 {
+		body, tags = text[:loc[0]], " "+strings.Join(strings.Fields(text[loc[0]:]), " ")
+		// Give up on the hashtags if they'd leave too little room for the text.
+		if length(tags) > limit/3 {
+			tags = ""
+		}
+	}
+
+	budget := (limit - length(ellipsis+tags)) * tweetTextScale
+	tokens := tokenize(body)
+	used, cut, lastSpace := 0, 0, -1
+	for i, t := range tokens {
+		if used+t.weight > budget {
+			break
+		}
+		used += t.weight
+		cut = i + 1
+		if t.space {
+			lastSpace = i
+		}
+	}
+	// Back off to the last word boundary unless that throws away too much.
+	if cut < len(tokens) && lastSpace > 0 && lastSpace >= cut*2/3 {
+		cut = lastSpace
+	}
+
+	var sb strings.Builder
+	for _, t := range tokens[:cut] {
+		sb.WriteString(t.text)
+	}
+	trimmed := strings.TrimRightFunc(sb.String(), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",;:-–—", r)
+	})
+	return trimmed + ellipsis + tags
+}
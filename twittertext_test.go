@@ -0,0 +1,139 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTweetWeightedLength(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"Müller scores – 2-1", 19},
+		{"日本語", 6},
+		{"😀", 2},
+		{"👍🏽", 2},      // skin tone modifier
+		{"👨‍👩‍👧‍👦", 2}, // ZWJ family sequence
+		{"🏴󠁧󠁢󠁳󠁣󠁴󠁿", 2}, // subdivision flag tag sequence
+		{"🇬🇧🇮🇪", 4},    // two flag pairs
+		{"1️⃣", 2},     // keycap
+		{"see https://example.com/a/very/long/path?q=1", 4 + 23},
+		{"www.example.com.", 23 + 1}, // trailing punctuation isn't part of the link
+		{"a https://x.co b https://y.co", 5 + 2*23},
+	}
+	for _, tt := range tests {
+		if got := tweetWeightedLength(tt.text); got != tt.want {
+			t.Errorf("tweetWeightedLength(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateTweet(t *testing.T) {
+	long := strings.Repeat("word ", 80)
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		check func(t *testing.T, got string)
+	}{
+		{
+			name:  "fits unchanged",
+			text:  "  Short tweet #Tag  ",
+			limit: 280,
+			check: func(t *testing.T, got string) {
+				if got != "Short tweet #Tag" {
+					t.Errorf("got %q", got)
+				}
+			},
+		},
+		{
+			name:  "cuts at a word boundary and keeps hashtags",
+			text:  long + "#Football #PremierLeague",
+			limit: 100,
+			check: func(t *testing.T, got string) {
+				if !strings.HasSuffix(got, "… #Football #PremierLeague") {
+					t.Errorf("hashtags not kept: %q", got)
+				}
+				if strings.Contains(got, "wor…") {
+					t.Errorf("cut inside a word: %q", got)
+				}
+			},
+		},
+		{
+			name:  "drops hashtags that leave too little room",
+			text:  long + "#" + strings.Repeat("x", 60),
+			limit: 100,
+			check: func(t *testing.T, got string) {
+				if strings.Contains(got, "#") {
+					t.Errorf("oversized hashtags kept: %q", got)
+				}
+			},
+		},
+		{
+			name:  "CJK",
+			text:  strings.Repeat("日本語", 60),
+			limit: 101,
+			check: func(t *testing.T, got string) {
+				if !strings.HasSuffix(got, "…") || !strings.HasPrefix(got, "日本語") {
+					t.Errorf("got %q", got)
+				}
+			},
+		},
+		{
+			name:  "never splits an emoji sequence",
+			text:  strings.Repeat("👨‍👩‍👧‍👦", 200),
+			limit: 280,
+			check: func(t *testing.T, got string) {
+				body := strings.TrimSuffix(got, "…")
+				if strings.ReplaceAll(body, "👨‍👩‍👧‍👦", "") != "" {
+					t.Errorf("split a ZWJ sequence: %q", got)
+				}
+			},
+		},
+		{
+			name:  "never splits a URL",
+			text:  strings.Repeat("a", 260) + " https://example.com/" + strings.Repeat("p", 100),
+			limit: 280,
+			check: func(t *testing.T, got string) {
+				if strings.Contains(got, "https://") && !strings.Contains(got, strings.Repeat("p", 100)) {
+					t.Errorf("split a URL: %q", got)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateTweet(tt.text, tt.limit)
+			if n := tweetWeightedLength(got); n > tt.limit {
+				t.Errorf("result is %d weighted characters, over %d: %q", n, tt.limit, got)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	// 150 CJK characters weigh 300 on X but are 150 characters elsewhere.
+	cjk := strings.Repeat("日本", 75)
+	if got := truncateGraphemes(cjk, 300); got != cjk {
+		t.Errorf("truncated text that fits: %q", got)
+	}
+	if got := truncateTweet(cjk, 280); got == cjk {
+		t.Error("X truncation kept text over its weighted limit")
+	}
+
+	long := strings.Repeat("⚽ goal ", 60) + "https://example.com/" + strings.Repeat("a", 40)
+	got := truncateGraphemes(long, 300)
+	if n := graphemeLength(got); n > 300 || n < 250 {
+		t.Errorf("truncated to %d characters, want up to 300: %q", n, got)
+	}
+	if !strings.HasSuffix(got, "…") {
+		t.Errorf("no ellipsis: %q", got)
+	}
+	if n := graphemeLength("Visit https://example.com/" + strings.Repeat("a", 40)); n != 6+60 {
+		t.Errorf("graphemeLength counts a URL as %d, want its length", n-6)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// errUnverified marks generated text that still contradicted its source
+// after every attempt.
+var errUnverified = errors.New("generated text failed fact verification")
+
+// generateVerified generates text and checks it against the source with
+// verify, regenerating with the rejection reason fed back to the model until
+// it passes or VERIFY_ATTEMPTS is used up.
+func (nb *NewsBot) generateVerified(ctx context.Context, topic *Topic, prompt string, opts GenerateOptions, verify func(string) error) (*Generation, error) {
+	attemptPrompt := prompt
+	var reason error
+	for attempt := 1; attempt <= nb.config.VerifyAttempts; attempt++ {
+		gen, err := nb.generateTweet(ctx, topic, attemptPrompt, opts)
+		if err != nil {
+			return nil, err
+		}
+		if reason = verify(gen.Text); reason == nil {
+			return gen, nil
+		}
+		log.Printf("Rejected %s draft %d/%d from %s: %v", topic.DisplayName, attempt, nb.config.VerifyAttempts, gen.Provider, reason)
+		attemptPrompt = fmt.Sprintf("%s\n\nA previous draft was rejected because %v. Use only the facts given above.", prompt, reason)
+	}
+	return nil, fmt.Errorf("%w: %v", errUnverified, reason)
+}
+
+var (
+	// A scoreline such as "3-1" or "2 – 2", but not part of a date or a
+	// longer number.
+	scorelineRe = regexp.MustCompile(`(?:^|[^\d\-/.:])(\d{1,2})\s?[-–—]\s?(\d{1,2})(?:$|[^\d\-/%])`)
+	// A figure such as "$64,000", "12.5%", "3bn" or "1.2 million".
+	figureRe = regexp.MustCompile(`(?i)\$?(\d[\d,]*(?:\.\d+)?)\s?(%|k\b|m\b|bn\b|b\b|million\b|billion\b|trillion\b)?`)
+	wordRe   = regexp.MustCompile(`[\pL\pN]+`)
+)
+
+// Words that don't identify a club on their own: legal forms and common
+// suffixes ("FC", "United", "City").
+var genericTeamWords = map[string]bool{
+	"fc": true, "afc": true, "cf": true, "sc": true, "ac": true, "as": true, "ssc": true,
+	"us": true, "sv": true, "vfb": true, "vfl": true, "tsg": true, "rc": true, "rcd": true,
+	"ogc": true, "cd": true, "ud": true, "ca": true, "bk": true, "club": true,
+	"de": true, "del": true, "la": true, "le": true, "of": true, "the": true, "and": true,
+	"united": true, "city": true, "town": true, "county": true, "athletic": true,
+	"albion": true, "rovers": true, "wanderers": true, "real": true, "sporting": true,
+	"football": true, "calcio": true, "olympique": true, "stade": true,
+}
+
+// foldWords lower-cases s, strips accents and splits it into words.
+func foldWords(s string) []string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			sb.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return wordRe.FindAllString(sb.String(), -1)
+}
+
+// mentionsTeam reports whether text names the team by any distinctive word
+// of its full or short name ("Wolverhampton", "Wolves", "Bayern"). Legal
+// forms, founding years and suffixes like "United" don't count.
+func mentionsTeam(textWords map[string]bool, team MatchTeam) bool {
+	checked := false
+	for _, w := range foldWords(team.Name + " " + team.ShortName) {
+		if genericTeamWords[w] || len(w) < 3 || strings.Trim(w, "0123456789") == "" {
+			continue
+		}
+		checked = true
+		if textWords[w] {
+			return true
+		}
+	}
+	return !checked // nothing distinctive to look for
+}
+
+// verifyTeams checks that text names both teams in the match.
+func verifyTeams(text string, match *PremierLeagueMatch) error {
+	textWords := make(map[string]bool)
+	for _, w := range foldWords(text) {
+		textWords[w] = true
+	}
+	for _, team := range []MatchTeam{match.HomeTeam, match.AwayTeam} {
+		if !mentionsTeam(textWords, team) {
+			return fmt.Errorf("it doesn't mention %s", team.Name)
+		}
+	}
+	return nil
+}
+
+// verifyMatchText checks that text names both teams, states the final score
+// and that every other scoreline it quotes is in the fact sheet: the
+// half-time score, a shootout or the score after one of the goals. Scores
+// may be in either order, since "Liverpool win 3-1 at Wolves" is as valid as
+// "Wolves 1-3 Liverpool".
+func verifyMatchText(text string, match *PremierLeagueMatch) error {
+	if err := verifyTeams(text, match); err != nil {
+		return err
+	}
+
+	home, away := match.Score.FullTime.Home, match.Score.FullTime.Away
+	known := [][2]int{{home, away}}
+	if match.Provider == "" {
+		known = append(known, [2]int{match.Score.HalfTime.Home, match.Score.HalfTime.Away})
+	}
+	if match.Score.Duration == "PENALTY_SHOOTOUT" {
+		known = append(known, [2]int{match.Score.Penalties.Home, match.Score.Penalties.Away})
+	}
+	for _, g := range match.Goals {
+		known = append(known, [2]int{g.Score.Home, g.Score.Away})
+	}
+	found := false
+	for _, m := range scorelineRe.FindAllStringSubmatch(text, -1) {
+		a, _ := strconv.Atoi(m[1])
+		b, _ := strconv.Atoi(m[2])
+		if (a == home && b == away) || (a == away && b == home) {
+			found = true
+			continue
+		}
+		if slices.ContainsFunc(known, func(s [2]int) bool { return (a == s[0] && b == s[1]) || (a == s[1] && b == s[0]) }) {
+			continue
+		}
+		return fmt.Errorf("it states the score as %d-%d but the result was %s %d-%d %s",
+			a, b, match.HomeTeam.Name, home, away, match.AwayTeam.Name)
+	}
+	if !found {
+		return fmt.Errorf("it doesn't state the %d-%d score", home, away)
+	}
+	return nil
+}
+
+// verifyPreviewText checks that a preview names both teams and doesn't state
+// a score for a match that hasn't been played.
+func verifyPreviewText(text string, match *PremierLeagueMatch) error {
+	if err := verifyTeams(text, match); err != nil {
+		return err
+	}
+	if m := scorelineRe.FindStringSubmatch(text); m != nil {
+		return fmt.Errorf("it states a score (%s-%s) for a match that hasn't been played", m[1], m[2])
+	}
+	return nil
+}
+
+// verifyArticleText checks that text is about the article, sharing at least
+// one significant word with its title, and that every figure it quotes
+// appears in the title or description.
+func verifyArticleText(text string, article *NewsAPIArticle) error {
+	textWords := make(map[string]bool)
+	for _, w := range foldWords(text) {
+		textWords[w] = true
+	}
+	var keywords []string
+	overlap := false
+	for _, w := range foldWords(article.Title) {
+		if len(w) < 4 || articleStopWords[w] {
+			continue
+		}
+		keywords = append(keywords, w)
+		if textWords[w] {
+			overlap = true
+		}
+	}
+	if len(keywords) > 0 && !overlap {
+		return fmt.Errorf("it shares no key words with the headline %q", article.Title)
+	}
+
+	source := extractFigures(article.Title + " " + article.Description)
+	for _, f := range extractFigures(text) {
+		if !f.in(source) {
+			return fmt.Errorf("it quotes the figure %s, which isn't in the article", f.raw)
+		}
+	}
+	return nil
+}
+
+var articleStopWords = map[string]bool{
+	"this": true, "that": true, "with": true, "from": true, "after": true, "over": true,
+	"into": true, "about": true, "says": true, "will": true, "what": true, "when": true,
+	"have": true, "just": true, "more": true, "than": true, "their": true, "news": true,
+}
+
+// figure is a number quoted in text, scaled by its unit ("$64K" -> 64000).
+type figure struct {
+	raw     string
+	value   float64
+	percent bool
+}
+
+var figureScale = map[string]float64{
+	"k": 1e3, "m": 1e6, "million": 1e6, "b": 1e9, "bn": 1e9, "billion": 1e9, "trillion": 1e12,
+}
+
+// in reports whether the figure matches one of the source figures, allowing
+// for rounding such as "$64K" for "$64,350".
+func (f figure) in(source []figure) bool {
+	for _, s := range source {
+		if s.percent != f.percent {
+			continue
+		}
+		if math.Abs(f.value-s.value) <= 0.02*s.value {
+			return true
+		}
+	}
+	return false
+}
+
+// extractFigures returns the numbers in s that are worth checking: prices,
+// percentages, amounts with a unit and large numbers. Plain counts ("24
+// hours") and years are skipped since the model often adds them as context.
+func extractFigures(s string) []figure {
+	var figures []figure
+	for _, m := range figureRe.FindAllStringSubmatch(s, -1) {
+		num := strings.ReplaceAll(m[1], ",", "")
+		v, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			continue
+		}
+		unit := strings.ToLower(m[2])
+		plain := unit == "" && !strings.HasPrefix(m[0], "$") && !strings.Contains(num, ".")
+		if plain && (v < 1000 || (v >= 1900 && v <= 2100)) {
+			continue
+		}
+		if scale, ok := figureScale[unit]; ok {
+			v *= scale
+		}
+		figures = append(figures, figure{raw: strings.TrimSpace(m[0]), value: v, percent: unit == "%"})
+	}
+	return figures
+}
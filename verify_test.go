@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScorelineRe(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string // scorelines found, as "a-b"
+	}{
+		{"Arsenal beat Chelsea 3-1", []string{"3-1"}},
+		{"A 2 – 2 draw", []string{"2-2"}},
+		{"won 4—0 at home", []string{"4-0"}},
+		{"1-0 at the break, 2-1 at full time", []string{"1-0", "2-1"}},
+		{"the 2024-25 season", nil},
+		{"on 2025-03-01", nil},
+		{"on 01/03-2025", nil},
+		{"kick-off 15:00-17:00", nil},
+		{"a 10-15% rise", nil},
+		{"score of 100-1", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, m := range scorelineRe.FindAllStringSubmatch(tt.text, -1) {
+			got = append(got, m[1]+"-"+m[2])
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("scorelines in %q = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func testMatch() *PremierLeagueMatch {
+	m := &PremierLeagueMatch{
+		HomeTeam: MatchTeam{ID: 76, Name: "Wolverhampton Wanderers FC", ShortName: "Wolves"},
+		AwayTeam: MatchTeam{ID: 64, Name: "Liverpool FC", ShortName: "Liverpool"},
+		Status:   "FINISHED",
+	}
+	m.Score.FullTime.Home, m.Score.FullTime.Away = 1, 3
+	m.Score.HalfTime.Home, m.Score.HalfTime.Away = 0, 2
+	return m
+}
+
+func TestVerifyMatchText(t *testing.T) {
+	goal := func(home, away int) MatchGoal {
+		var g MatchGoal
+		g.Score.Home, g.Score.Away = home, away
+		return g
+	}
+	tests := []struct {
+		name    string
+		text    string
+		goals   []MatchGoal
+		wantErr string
+	}{
+		{"home-first score", "Wolves 1-3 Liverpool. #LFC", nil, ""},
+		{"winner-first score", "Liverpool win 3-1 at Wolverhampton!", nil, ""},
+		{"en dash", "Liverpool win 3–1 at Wolves", nil, ""},
+		{"season isn't a score", "Liverpool win 3-1 at Wolves in the 2024-25 season", nil, ""},
+		{"half-time score", "Liverpool led 2-0 at the break and won 3-1 at Wolves", nil, ""},
+		{"running score from a goal", "Wolves pulled it back to 1-2 but Liverpool won 3-1", []MatchGoal{goal(0, 1), goal(0, 2), goal(1, 2), goal(1, 3)}, ""},
+		{"running score without goal data", "Wolves pulled it back to 1-2 but Liverpool won 3-1", nil, "states the score as 1-2"},
+		{"wrong score", "Liverpool win 2-1 at Wolves", nil, "states the score as 2-1"},
+		{"no score", "Liverpool win at Wolves", nil, "doesn't state the 1-3 score"},
+		{"missing team", "Liverpool win 3-1 away from home", nil, "doesn't mention Wolverhampton"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testMatch()
+			m.Goals = tt.goals
+			err := verifyMatchText(tt.text, m)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyMatchTextIgnoresHalfTimeFromOtherProviders(t *testing.T) {
+	m := testMatch()
+	m.Provider = "thesportsdb" // no half-time score, so 0-2 isn't a fact
+	if err := verifyMatchText("Liverpool led 2-0 at the break and won 3-1 at Wolves", m); err == nil {
+		t.Error("accepted a half-time score the provider doesn't report")
+	}
+}
+
+func TestVerifyPreviewText(t *testing.T) {
+	m := testMatch()
+	if err := verifyPreviewText("Wolves host Liverpool at 3pm in the 2024-25 run-in", m); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := verifyPreviewText("Wolves host Liverpool; we predict 2-1", m); err == nil {
+		t.Error("accepted a predicted score")
+	}
+}